@@ -1,32 +1,91 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"kcommit/src"
 )
 
 func main() {
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
+	command := ""
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
 		case "--version", "-v":
 			fmt.Println(src.KcVersion)
 			return
 		}
 	}
 
+	// kc changelog takes the revision range before the flags.
+	revisionRange := ""
+	if command == "changelog" && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		revisionRange = args[0]
+		args = args[1:]
+	}
+
+	commandLine, err := src.ParseCommandLine(args, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	fileManager, err := src.NewFileManager()
 	if err != nil {
 		log.Fatalln(err, "Failed to initialize FileManager")
 	}
 
-	git := src.NewGit()
+	userConfig, err := src.LoadUserConfig(fileManager)
+	if err != nil {
+		log.Fatalln(err, "Failed to load kcommit config")
+	}
+
+	if err := src.SetTheme(userConfig.Theme); err != nil {
+		log.Fatalln(err, "Failed to load kcommit theme")
+	}
+
+	git, err := src.NewGitBackend(userConfig.GitBackendName())
+	if err != nil {
+		log.Fatalln(err, "Failed to initialize git backend")
+	}
+
 	utils := src.NewUtils()
-	viewBuilder := src.NewViewBuilder()
+	plain, err := userConfig.UsePlainPrompts(commandLine.Plain, src.IsTerminal())
+	if err != nil {
+		log.Fatalln(err, "Failed to load kcommit config")
+	}
+
+	var viewBuilder src.ViewBuilderInterface = src.NewViewBuilder()
+	if plain {
+		viewBuilder = src.NewPlainViewBuilder(os.Stdin, os.Stdout)
+	}
 
 	runner := src.NewRunner(fileManager, git, utils, viewBuilder)
+	runner.SetCommitOptions(commandLine.CommitOptions)
 
-	runner.Start()
+	switch command {
+	case "amend":
+		runner.Amend()
+	case src.FixupCommit, src.SquashCommit:
+		runner.Fixup(command)
+	case "revert":
+		runner.Revert()
+	case "changelog":
+		runner.Changelog(revisionRange, commandLine.JSON)
+	default:
+		runner.Start()
+	}
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -153,10 +156,69 @@ func TestCleanOldBranchesYear(t *testing.T) {
 	assertBranchExists(t, history, "ProjectA", "Branch1", false)
 }
 
+func TestHistoryRankTypes(t *testing.T) {
+	now := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	types := src.DefaultRules().CommitTypeDTOs[:4]
+
+	history := src.History{Projects: map[string]map[string]src.BranchDetail{}}
+	history.AddBranch("project", "feature")
+	history.AddBranch("project", "maintenance")
+
+	for i := 0; i < 3; i++ {
+		history.RecordType("project", "feature", "feat", now.AddDate(0, 0, -1))
+	}
+	history.RecordType("project", "maintenance", "fix", now)
+	// An old use counts less than a recent one.
+	history.RecordType("project", "maintenance", "style", now.AddDate(0, 0, -21))
+	history.RecordType("project", "maintenance", "style", now.AddDate(0, 0, -21))
+
+	tests := []struct {
+		branch   string
+		expected []string
+	}{
+		{"feature", []string{"feat", "fix", "style", "chore"}},
+		{"maintenance", []string{"fix", "style", "feat", "chore"}},
+		{"new-branch", []string{"feat", "fix", "style", "chore"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, commitType := range history.RankTypes("project", test.branch, types, now) {
+			got = append(got, commitType.Type)
+		}
+
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("expected %v on %s, got %v", test.expected, test.branch, got)
+		}
+	}
+
+	if types[0].Type != "feat" || types[1].Type != "fix" {
+		t.Errorf("expected the given types to be left in their order")
+	}
+}
+
+func TestHistoryRecordDescription(t *testing.T) {
+	history := src.History{Projects: map[string]map[string]src.BranchDetail{}}
+
+	for i := 0; i < src.RecentDescriptionsLimit+5; i++ {
+		history.RecordDescription("project", fmt.Sprintf("change %d", i))
+	}
+	history.RecordDescription("project", "change 10")
+
+	descriptions := history.RecentDescriptions("project")
+	if len(descriptions) != src.RecentDescriptionsLimit {
+		t.Fatalf("expected %d descriptions, got %d", src.RecentDescriptionsLimit, len(descriptions))
+	}
+
+	if descriptions[0] != "change 10" || descriptions[1] != fmt.Sprintf("change %d", src.RecentDescriptionsLimit+4) {
+		t.Errorf("expected the last description first without duplicates, got %v", descriptions[:2])
+	}
+}
+
 func TestTextFieldViewModelClearsViewOnCancel(t *testing.T) {
 	for _, keyType := range []tea.KeyType{tea.KeyEsc, tea.KeyCtrlC} {
 		endValue := ""
-		model := src.TextFieldViewModel("Commit message", "", &endValue)
+		model := src.TextFieldViewModel("Commit message", "", &endValue, nil)
 
 		updatedModel, cmd := model.Update(tea.KeyMsg{Type: keyType})
 
@@ -174,6 +236,32 @@ func TestTextFieldViewModelClearsViewOnCancel(t *testing.T) {
 	}
 }
 
+func TestTextFieldViewModelGoesBack(t *testing.T) {
+	endValue := ""
+	model := src.TextFieldViewModel("Scope", "", &endValue, nil)
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	if endValue != "" {
+		t.Errorf("expected backspace to delete while there is text, got %q", endValue)
+	}
+
+	_, cmd := updatedModel.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	if cmd == nil || endValue != src.BackSignal {
+		t.Errorf("expected back signal on backspace in an empty field, got %q", endValue)
+	}
+
+	endValue = "cache"
+	model = src.TextFieldViewModel("Scope", "", &endValue, nil)
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+
+	if endValue != src.BackSignal {
+		t.Errorf("expected back signal on shift+tab, got %q", endValue)
+	}
+}
+
 // --- Test mocks ---
 
 func TestFileManagerMock(t *testing.T) {
@@ -330,7 +418,7 @@ func TestViewBuilderMock(t *testing.T) {
 		return
 	}
 
-	respTextField := mock.NewTextFieldView("", "")
+	respTextField := mock.NewTextFieldView("", "", nil)
 
 	if respTextField != "newTextField" {
 		t.Errorf("ViewBuilderMock NewTextFieldView failed")
@@ -363,6 +451,810 @@ func TestRunnerHappyPath(t *testing.T) {
 	}
 }
 
+func TestRunnerForwardsCommitOptions(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		CheckIfPathExistsReturns: map[string]interface{}{
+			src.KcommitRcFileName: true,
+		},
+		ReadFileContentReturns: map[string]interface{}{
+			src.KcommitRcFileName: `{"commitOptions": {"signoff": true}}`,
+		},
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "feat", Description: "add eviction"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	noVerify := true
+	r.SetCommitOptions(src.CommitOptionsDTO{NoVerify: &noVerify})
+	r.Start()
+
+	expected := []string{"--no-verify", "--signoff"}
+	if !containsSame(git.GitCommitCalledArgs, expected) {
+		t.Errorf("expected commit args %v, got %v", expected, git.GitCommitCalledArgs)
+	}
+}
+
+func TestRunnerDetachedHeadIsNotSaved(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:    true,
+		GetRepositoryStateReturnValue: src.RepositoryState{Detached: true},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewTextFieldViewReturnValue: "hotfix",
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "fix", Scope: "hotfix", Description: "handle nil"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if git.GetCurrentBranchCalled != 0 {
+		t.Errorf("expected current branch not to be read on detached HEAD")
+	}
+
+	if scope := viewBuilder.NewComposerViewCalledWith[0].Message.Scope; scope != "hotfix" {
+		t.Errorf("expected composer pre-filled with the scope, got %q", scope)
+	}
+
+	if git.GitCommitReturnValue != "fix(hotfix): handle nil" {
+		t.Errorf("unexpected commit message %q", git.GitCommitReturnValue)
+	}
+
+	if strings.Contains(fileManager.WriteHistoryContentWrittenContent, "hotfix") {
+		t.Errorf("expected detached HEAD scope not to be saved, got %s", fileManager.WriteHistoryContentWrittenContent)
+	}
+}
+
+func TestRunnerRebaseUsesRebasedBranch(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns:           `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}]}]}`,
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRepositoryStateReturnValue: src.RepositoryState{
+			Operation: src.RebaseOperation,
+			Detached:  true,
+			Branch:    "feature",
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "fix", Scope: "cache", Description: "handle nil"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if git.GetCurrentBranchCalled != 0 {
+		t.Errorf("expected current branch not to be read during rebase")
+	}
+
+	if viewBuilder.NewTextFieldViewCalled != 0 || viewBuilder.NewListViewCalled != 0 {
+		t.Errorf("expected only the composer to be shown, got %d text fields and %d lists", viewBuilder.NewTextFieldViewCalled, viewBuilder.NewListViewCalled)
+	}
+
+	if scope := viewBuilder.NewComposerViewCalledWith[0].Message.Scope; scope != "cache" {
+		t.Errorf("expected the scope of the rebased branch, got %q", scope)
+	}
+}
+
+func TestRunnerKeepsPreparedMergeMessage(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRepositoryStateReturnValue: src.RepositoryState{
+			Operation:       src.MergeOperation,
+			PreparedMessage: "Merge branch 'feature'",
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValue: "keep",
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if git.GitCommitReturnValue != "Merge branch 'feature'" {
+		t.Errorf("expected prepared merge message, got %q", git.GitCommitReturnValue)
+	}
+
+	if viewBuilder.NewListViewCalled != 1 {
+		t.Errorf("expected kcommit to stop after keeping the prepared message")
+	}
+}
+
+func TestRunnerSavesDraftOnCancel(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns:           `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}]}]}`,
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{
+				Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add a carefully worded description", Body: "With a body."},
+				Action:  src.ExitSignal,
+			},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if len(fileManager.WriteHistoryContentWrittenContents) == 0 {
+		t.Fatalf("expected draft to be written on cancel")
+	}
+
+	history, err := src.ParseJSONContent[src.HistoryDTO](fileManager.WriteHistoryContentWrittenContents[0])
+	if err != nil {
+		t.Fatalf("failed to parse written history: %v", err)
+	}
+
+	model := history.ToModel()
+	branch, err := model.FindBranchData("project", "feature")
+	if err != nil {
+		t.Fatalf("expected branch on history: %v", err)
+	}
+
+	expected := src.DraftDTO{Type: "feat", Scope: "cache", Description: "add a carefully worded description", Body: "With a body."}
+	if branch.Draft == nil || *branch.Draft != expected {
+		t.Errorf("expected draft %+v, got %+v", expected, branch.Draft)
+	}
+}
+
+func TestRunnerResumesDraft(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns: `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache",
+			"draft": {"type": "fix", "scope": "cache", "description": "handle nil entries"}}]}]}`,
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValue: "resume",
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	expected := src.CommitMessage{Type: "fix", Scope: "cache", Description: "handle nil entries"}
+	if message := viewBuilder.NewComposerViewCalledWith[0].Message; message != expected {
+		t.Errorf("expected composer pre-filled with the draft %+v, got %+v", expected, message)
+	}
+
+	if strings.Contains(fileManager.WriteHistoryContentWrittenContent, "draft") {
+		t.Errorf("expected draft to be removed once used, got %s", fileManager.WriteHistoryContentWrittenContent)
+	}
+}
+
+func TestRunnerRanksCommitTypes(t *testing.T) {
+	usedAt := time.Now().Format(time.RFC3339)
+
+	for _, pinned := range []bool{false, true} {
+		fileManager := testresources.FileManagerMock{
+			GetCurrentDirectoryNameReturnValue: "project",
+			GetHistoryContentReturns: `{"projects": [{"name": "project", "branches": [{"name": "maintenance", "scope": "core",
+				"types": [{"type": "fix", "count": 4, "last_used": "` + usedAt + `"}]}]}]}`,
+			CheckIfPathExistsReturns: map[string]interface{}{
+				src.KcommitRcFileName: true,
+			},
+			ReadFileContentReturns: map[string]interface{}{
+				src.KcommitRcFileName: fmt.Sprintf(`{"pinTypeOrder": %v}`, pinned),
+			},
+		}
+
+		utils := testresources.UtilsMock{}
+
+		git := testresources.GitMock{
+			IsGitRepositoryReturnValue:  true,
+			GetCurrentBranchReturnValue: "maintenance",
+		}
+
+		viewBuilder := testresources.ViewBuilderMock{
+			NewComposerViewReturnValues: []src.ComposerResult{
+				{Message: src.CommitMessage{Type: "fix", Scope: "core", Description: "handle nil entries"}, Action: "commit"},
+			},
+		}
+
+		r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+		r.Start()
+
+		expected := map[bool]string{false: "fix", true: "feat"}[pinned]
+		if first := viewBuilder.NewComposerViewCalledWith[0].Types[0].T; first != expected {
+			t.Errorf("expected %s to be listed first with pinTypeOrder %v, got %s", expected, pinned, first)
+		}
+
+		if !strings.Contains(fileManager.WriteHistoryContentWrittenContent, `"count": 5`) {
+			t.Errorf("expected the use of fix to be recorded, got %s", fileManager.WriteHistoryContentWrittenContent)
+		}
+	}
+}
+
+func TestRunnerRecordsOnlyCommittedMessages(t *testing.T) {
+	usedAt := time.Now().Format(time.RFC3339)
+
+	tests := map[string]struct {
+		action    string
+		commitErr error
+	}{
+		"just print":  {action: "just print"},
+		"hook failed": {action: "commit", commitErr: &src.GitError{Command: []string{"commit"}, Category: src.HookFailedError}},
+	}
+
+	for name, test := range tests {
+		fileManager := testresources.FileManagerMock{
+			GetCurrentDirectoryNameReturnValue: "project",
+			GetHistoryContentReturns: `{"projects": [{"name": "project", "branches": [{"name": "maintenance", "scope": "core",
+				"types": [{"type": "fix", "count": 4, "last_used": "` + usedAt + `"}]}]}]}`,
+		}
+
+		utils := testresources.UtilsMock{}
+
+		git := testresources.GitMock{
+			IsGitRepositoryReturnValue:  true,
+			GetCurrentBranchReturnValue: "maintenance",
+			GitCommitReturnErrors:       []error{test.commitErr},
+		}
+
+		viewBuilder := testresources.ViewBuilderMock{
+			NewComposerViewReturnValues: []src.ComposerResult{
+				{Message: src.CommitMessage{Type: "fix", Scope: "core", Description: "handle nil entries"}, Action: test.action},
+			},
+		}
+
+		r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+		r.Start()
+
+		history, err := src.ParseJSONContent[src.HistoryDTO](fileManager.WriteHistoryContentWrittenContent)
+		if err != nil {
+			t.Fatalf("%s: failed to parse the history: %v", name, err)
+		}
+
+		project := history.Projects[0]
+		if count := project.Branches[0].Types[0].Count; count != 4 || len(project.Descriptions) != 0 {
+			t.Errorf("%s: expected nothing recorded, got fix used %d times and descriptions %v", name, count, project.Descriptions)
+		}
+	}
+}
+
+func TestRunnerSuggestsDescriptions(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns: `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}],
+			"descriptions": ["address review comments"]}]}`,
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Subject: "fix(cache): address review comments"},
+			{Subject: "feat(cache): add eviction"},
+			{Subject: "Merge branch 'main'"},
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "fix", Scope: "cache", Description: "handle nil entries"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	expected := []string{"address review comments", "add eviction"}
+	if suggestions := viewBuilder.NewComposerViewCalledWith[0].DescriptionSuggestions; !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("expected suggestions %v, got %v", expected, suggestions)
+	}
+
+	if !strings.Contains(fileManager.WriteHistoryContentWrittenContent, `"handle nil entries",
+        "address review comments"`) {
+		t.Errorf("expected the description to be recorded first, got %s", fileManager.WriteHistoryContentWrittenContent)
+	}
+}
+
+func TestRunnerSuggestsScopes(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns: `{"projects": [{"name": "project", "branches": [
+			{"name": "login", "scope": "authentication", "updated_at": "2024-11-01T00:00:00Z"},
+			{"name": "cache", "scope": "cache", "updated_at": "2024-11-20T00:00:00Z"}]}]}`,
+		CheckIfPathExistsReturns: map[string]interface{}{
+			src.KcommitRcFileName: true,
+		},
+		ReadFileContentReturns: map[string]interface{}{
+			src.KcommitRcFileName: `{"scopes": ["auth", "api"]}`,
+		},
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Subject: "fix(ui): align the buttons"},
+			{Subject: "feat(auth): add login"},
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues:      []string{"custom"},
+		NewTextFieldViewReturnValues: []string{"auth"},
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "feat", Scope: "auth", Description: "add logout"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	expected := []string{"auth", "api", "cache", "authentication", "ui"}
+	if len(viewBuilder.NewTextFieldViewSuggestions) != 1 || !reflect.DeepEqual(viewBuilder.NewTextFieldViewSuggestions[0], expected) {
+		t.Errorf("expected the scope name to suggest %v, got %v", expected, viewBuilder.NewTextFieldViewSuggestions)
+	}
+
+	if suggestions := viewBuilder.NewComposerViewCalledWith[0].ScopeSuggestions; !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("expected the composer to suggest %v, got %v", expected, suggestions)
+	}
+}
+
+func TestRunnerUsesPreset(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns:           `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}]}]}`,
+		CheckIfPathExistsReturns: map[string]interface{}{
+			src.KcommitRcFileName: true,
+		},
+		ReadFileContentReturns: map[string]interface{}{
+			src.KcommitRcFileName: `{"preset": "gitmoji", "pinTypeOrder": true}`,
+		},
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if first := viewBuilder.NewComposerViewCalledWith[0].Types[0]; first.T != "feat" || first.D != "✨ Introduces a new feature." {
+		t.Errorf("expected the gitmoji types, got %+v", first)
+	}
+
+	if git.GitCommitReturnValue != "✨ feat(cache): add eviction" {
+		t.Errorf("unexpected commit message %q", git.GitCommitReturnValue)
+	}
+}
+
+func TestRunnerExtendsSharedRules(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns:           `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}]}]}`,
+		CheckIfPathExistsReturns: map[string]interface{}{
+			src.KcommitRcFileName: true,
+		},
+		ReadFileContentReturns: map[string]interface{}{
+			src.KcommitRcFileName: `{"extends": "kcommit.json", "commitTypes": [{"type": "spike"}], "pinTypeOrder": true}`,
+			"kcommit.json":        `{"preset": "angular", "headerTemplate": "{type}: {description}"}`,
+		},
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "spike", Scope: "cache", Description: "try a new cache"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	types := viewBuilder.NewComposerViewCalledWith[0].Types
+	if types[0].T != "build" || types[len(types)-1].T != "spike" {
+		t.Errorf("expected the angular types of the shared rules with spike added, got %+v", types)
+	}
+
+	if git.GitCommitReturnValue != "spike: try a new cache" {
+		t.Errorf("unexpected commit message %q", git.GitCommitReturnValue)
+	}
+}
+
+func TestRunnerChangelog(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		CheckIfPathExistsReturns: map[string]interface{}{
+			src.KcommitRcFileName: true,
+		},
+		ReadFileContentReturns: map[string]interface{}{
+			src.KcommitRcFileName: `{"changelog": {"sections": {"feat": "Features"}}}`,
+		},
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetLatestTagReturnValue:    "v1.2.0",
+		GetCommitsReturnValue: []src.LogEntry{
+			{ShortHash: "abc1234", Subject: "feat(cache)!: add eviction"},
+		},
+	}
+
+	var output strings.Builder
+
+	r := src.NewRunner(&fileManager, &git, &utils, &testresources.ViewBuilderMock{})
+	r.SetOutput(&output)
+	r.Changelog("", false)
+
+	if git.GetCommitsCalledWith != "v1.2.0..HEAD" {
+		t.Errorf("expected the commits since the latest tag, got %q", git.GetCommitsCalledWith)
+	}
+
+	if !strings.Contains(output.String(), "## Features\n\n### cache\n\n- ⚠ add eviction (abc1234)") {
+		t.Errorf("unexpected changelog:\n%s", output.String())
+	}
+
+	output.Reset()
+	git.GetLatestTagReturnValue = ""
+	r.Changelog("", true)
+
+	if git.GetCommitsCalledWith != "HEAD" || !strings.HasPrefix(output.String(), "{") {
+		t.Errorf("expected a JSON changelog of every commit, got %q for %q", output.String(), git.GetCommitsCalledWith)
+	}
+}
+
+func TestRunnerWritesMessageInEditor(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+		GetHistoryContentReturns:           `{"projects": [{"name": "project", "branches": [{"name": "feature", "scope": "cache"}]}]}`,
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}, Action: src.EditorSignal},
+			{Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction", Body: "Old entries are removed after a day."}, Action: "commit"},
+		},
+		NewEditorViewReturnValues: []string{
+			"feature(cache): add eviction\n# comment",
+			"feat(cache): add eviction\n\nOld entries are removed after a day.\n# comment",
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	if len(viewBuilder.NewEditorViewCalledWith) != 2 {
+		t.Fatalf("expected the editor to be opened again after an invalid type, got %d", len(viewBuilder.NewEditorViewCalledWith))
+	}
+
+	if !strings.HasPrefix(viewBuilder.NewEditorViewCalledWith[0], "feat(cache): add eviction\n") {
+		t.Errorf("expected header pre-filled, got %q", viewBuilder.NewEditorViewCalledWith[0])
+	}
+
+	if !strings.Contains(viewBuilder.NewEditorViewCalledWith[1], "# Error: feature is not an allowed commit type") {
+		t.Errorf("expected the problem on the second round, got %q", viewBuilder.NewEditorViewCalledWith[1])
+	}
+
+	confirm := viewBuilder.NewComposerViewCalledWith[1]
+	if confirm.Focus != src.ConfirmPane || confirm.Message.Body != "Old entries are removed after a day." {
+		t.Errorf("expected the composer to confirm the edited message, got %+v", confirm)
+	}
+
+	expected := "feat(cache): add eviction\n\nOld entries are removed after a day."
+	if git.GitCommitReturnValue != expected {
+		t.Errorf("expected commit with %q, got %q", expected, git.GitCommitReturnValue)
+	}
+}
+
+func TestRunnerGoesBackToPreviousPrompt(t *testing.T) {
+	fileManager := testresources.FileManagerMock{
+		GetCurrentDirectoryNameReturnValue: "project",
+	}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:  true,
+		GetCurrentBranchReturnValue: "feature",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues:      []string{"custom"},
+		NewTextFieldViewReturnValues: []string{src.BackSignal, "cache"},
+		NewComposerViewReturnValues: []src.ComposerResult{
+			{Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}, Action: src.BackSignal},
+			{Message: src.CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}, Action: "commit"},
+		},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Start()
+
+	// Back from the scope name shows the scope choice with custom selected.
+	if !containsSame(viewBuilder.NewListViewWithSelectionCalledWith, []string{"custom"}) {
+		t.Errorf("expected the scope choice shown again with custom selected, got %v", viewBuilder.NewListViewWithSelectionCalledWith)
+	}
+
+	// Back from the composer shows the scope name pre-filled.
+	if !containsSame(viewBuilder.NewTextFieldViewWithValueCalledWith, []string{"cache"}) {
+		t.Errorf("expected the scope name shown again pre-filled, got %v", viewBuilder.NewTextFieldViewWithValueCalledWith)
+	}
+
+	if len(viewBuilder.NewComposerViewCalledWith) != 2 || viewBuilder.NewComposerViewCalledWith[1].Message.Description != "add eviction" {
+		t.Errorf("expected the composer shown again with the message, got %+v", viewBuilder.NewComposerViewCalledWith)
+	}
+
+	if git.GitCommitReturnValue != "feat(cache): add eviction" {
+		t.Errorf("unexpected commit message %q", git.GitCommitReturnValue)
+	}
+}
+
+func TestRunnerAmend(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue:      true,
+		GetLastCommitMessageReturnValue: "fix(login): handle empty password\n\nUsers could log in without one.",
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Amend()
+
+	message := src.CommitMessage{Type: "fix", Scope: "login", Description: "handle empty password", Body: "Users could log in without one."}
+	if len(viewBuilder.NewComposerViewCalledWith) != 1 || viewBuilder.NewComposerViewCalledWith[0].Message != message {
+		t.Fatalf("expected composer pre-filled with %+v, got %+v", message, viewBuilder.NewComposerViewCalledWith)
+	}
+
+	viewBuilder.NewComposerViewReturnValues = []src.ComposerResult{{Message: message, Action: "amend"}}
+	r.Amend()
+
+	expected := "fix(login): handle empty password\n\nUsers could log in without one."
+	if git.GitCommitAmendCalled != 1 || git.GitCommitAmendReturnValue != expected {
+		t.Errorf("expected amend with %q, got %q", expected, git.GitCommitAmendReturnValue)
+	}
+}
+
+func TestRunnerFixup(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Hash: "a1b2c3d4e5", ShortHash: "a1b2c3d", Subject: "feat(cache): add eviction"},
+			{Hash: "e4f5a6b7c8", ShortHash: "e4f5a6b", Subject: "chore(deps): update"},
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues: []string{"e4f5a6b", "commit and autosquash"},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Fixup(src.FixupCommit)
+
+	if git.GitCommitReturnValue != "fixup! chore(deps): update" {
+		t.Errorf("unexpected fixup message %q", git.GitCommitReturnValue)
+	}
+
+	if git.GitAutosquashCalled != 1 || git.GitAutosquashCalledWith != "e4f5a6b7c8" {
+		t.Errorf("expected autosquash onto e4f5a6b7c8, got %q", git.GitAutosquashCalledWith)
+	}
+}
+
+func TestRunnerFixupUnknownCommit(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Hash: "a1b2c3d4e5", ShortHash: "a1b2c3d", Subject: "feat(cache): add eviction"},
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues: []string{"0000000"},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Fixup(src.FixupCommit)
+
+	if utils.ExitWithErrorCalledWith != "0000000 is not one of the recent commits" {
+		t.Errorf("expected an error for an unknown commit, got %q", utils.ExitWithErrorCalledWith)
+	}
+}
+
+func TestRunnerOffersStagingWhenNothingToCommit(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Hash: "a1b2c3d4e5", ShortHash: "a1b2c3d", Subject: "feat(cache): add eviction"},
+		},
+		GitCommitReturnErrors: []error{&src.GitError{Command: []string{"commit"}, Category: src.NothingToCommitError}},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues: []string{"a1b2c3d"},
+		NewListViewReturnValue:  "stage all",
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Fixup(src.FixupCommit)
+
+	if git.StageAllCalled != 1 || git.GitCommitCalled != 2 {
+		t.Errorf("expected staging and a second commit, got %d stage and %d commits", git.StageAllCalled, git.GitCommitCalled)
+	}
+}
+
+func TestRunnerShowsHookFailure(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Hash: "a1b2c3d4e5", ShortHash: "a1b2c3d", Subject: "feat(cache): add eviction"},
+		},
+		GitCommitReturnErrors: []error{&src.GitError{Command: []string{"commit"}, Stderr: "lint failed", Category: src.HookFailedError}},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues: []string{"a1b2c3d", "commit"},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Fixup(src.FixupCommit)
+
+	if !strings.HasPrefix(utils.ExitWithErrorCalledWith, "Commit rejected by git hook:\nlint failed\n") {
+		t.Errorf("expected hook failure message with the hook output, got %q", utils.ExitWithErrorCalledWith)
+	}
+
+	if fileManager.WriteLastMessageWrittenContent != "fixup! feat(cache): add eviction" {
+		t.Errorf("expected commit message to be saved, got %q", fileManager.WriteLastMessageWrittenContent)
+	}
+
+	if viewBuilder.NewProgressViewCalled != 1 || len(git.SetOutputCalledWith) != 2 || git.SetOutputCalledWith[1] != nil {
+		t.Errorf("expected git output to be streamed to the progress view and reset after")
+	}
+
+	if git.StageAllCalled != 0 {
+		t.Errorf("expected no staging on hook failure")
+	}
+}
+
+func TestRunnerRevert(t *testing.T) {
+	fileManager := testresources.FileManagerMock{}
+
+	utils := testresources.UtilsMock{}
+
+	git := testresources.GitMock{
+		IsGitRepositoryReturnValue: true,
+		GetRecentCommitsReturnValue: []src.LogEntry{
+			{Hash: "e4f5a6b7c8", ShortHash: "e4f5a6b", Subject: "feat(cache): add eviction"},
+		},
+	}
+
+	viewBuilder := testresources.ViewBuilderMock{
+		NewListViewReturnValues: []string{"e4f5a6b", "commit"},
+	}
+
+	r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+	r.Revert()
+
+	if git.GitRevertNoCommitCalledWith != "e4f5a6b7c8" {
+		t.Errorf("expected revert of e4f5a6b7c8, got %q", git.GitRevertNoCommitCalledWith)
+	}
+
+	expected := "revert(cache): feat(cache): add eviction\n\nThis reverts commit e4f5a6b7c8."
+	if git.GitCommitReturnValue != expected {
+		t.Errorf("expected revert message %q, got %q", expected, git.GitCommitReturnValue)
+	}
+}
+
+func TestRunnerRevertCancelledBeforeApplying(t *testing.T) {
+	for _, answer := range []string{src.ExitSignal, "just print"} {
+		fileManager := testresources.FileManagerMock{}
+
+		utils := testresources.UtilsMock{}
+
+		git := testresources.GitMock{
+			IsGitRepositoryReturnValue: true,
+			GetRecentCommitsReturnValue: []src.LogEntry{
+				{Hash: "e4f5a6b7c8", ShortHash: "e4f5a6b", Subject: "feat(cache): add eviction"},
+			},
+		}
+
+		viewBuilder := testresources.ViewBuilderMock{
+			NewListViewReturnValues: []string{"e4f5a6b", answer},
+		}
+
+		r := src.NewRunner(&fileManager, &git, &utils, &viewBuilder)
+		r.Revert()
+
+		if git.GitRevertNoCommitCalled != 0 || git.GitCommitCalled != 0 {
+			t.Errorf("expected nothing to be reverted on %s, got %d reverts and %d commits", answer, git.GitRevertNoCommitCalled, git.GitCommitCalled)
+		}
+	}
+}
+
 // --- helpers ---
 
 func containsSame(list1, list2 []string) bool {
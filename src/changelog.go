@@ -0,0 +1,289 @@
+package src
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Footers that describe a breaking change in the body of a commit.
+var breakingChangeFooters = []string{"BREAKING CHANGE:", "BREAKING-CHANGE:"}
+
+// ChangelogEntry is a commit of the changelog.
+type ChangelogEntry struct {
+	Hash        string `json:"hash"`
+	ShortHash   string `json:"shortHash"`
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	// Breaking is set by a ! after the type and scope of the header or by a BREAKING CHANGE footer.
+	Breaking bool `json:"breaking"`
+	// BreakingNote is the text of the BREAKING CHANGE footer.
+	BreakingNote string `json:"breakingNote,omitempty"`
+}
+
+// ChangelogScope holds the entries of a section with the same scope.
+type ChangelogScope struct {
+	Scope   string           `json:"scope"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// ChangelogSection holds the entries of a commit type, grouped by scope.
+type ChangelogSection struct {
+	// Type is empty for the commits that do not follow the header template.
+	Type   string           `json:"type"`
+	Title  string           `json:"title"`
+	Scopes []ChangelogScope `json:"scopes"`
+}
+
+type Changelog struct {
+	Range string `json:"range"`
+	// Breaking repeats the breaking changes of the sections, so they are read first.
+	Breaking []ChangelogEntry   `json:"breaking"`
+	Sections []ChangelogSection `json:"sections"`
+}
+
+// extend returns the changelog rules with the section titles of other added.
+func (c *ChangelogRulesDTO) extend(other *ChangelogRulesDTO) *ChangelogRulesDTO {
+	if c == nil {
+		return other
+	}
+	if other == nil {
+		return c
+	}
+
+	extended := *c
+	extended.Sections = maps.Clone(c.Sections)
+	if extended.Sections == nil {
+		extended.Sections = map[string]string{}
+	}
+	maps.Copy(extended.Sections, other.Sections)
+	return &extended
+}
+
+// BuildChangelog parses the commits with the rules and groups them by commit type, in the order of the
+// rules, and by scope. Commits that do not follow the header template are grouped last.
+func BuildChangelog(revisionRange string, commits []LogEntry, rules *CommitRulesDTO) Changelog {
+	changelog := Changelog{Range: revisionRange, Breaking: []ChangelogEntry{}, Sections: []ChangelogSection{}}
+
+	entries := map[string][]ChangelogEntry{}
+	for _, commit := range commits {
+		entry := parseChangelogEntry(commit, rules)
+
+		if entry.Breaking {
+			changelog.Breaking = append(changelog.Breaking, entry)
+		}
+
+		section := entry.Type
+		if rules.findType(section).Type == "" {
+			section = ""
+		}
+		entries[section] = append(entries[section], entry)
+	}
+
+	types := make([]string, 0, len(rules.CommitTypeDTOs)+1)
+	for _, commitType := range rules.CommitTypeDTOs {
+		types = append(types, commitType.Type)
+	}
+	types = append(types, "")
+
+	for _, commitType := range types {
+		if len(entries[commitType]) == 0 {
+			continue
+		}
+
+		changelog.Sections = append(changelog.Sections, ChangelogSection{
+			Type:   commitType,
+			Title:  rules.changelogTitle(commitType),
+			Scopes: groupByScope(entries[commitType]),
+		})
+		// A type listed twice, e.g. by a preset and .kcommitrc, gets a single section.
+		delete(entries, commitType)
+	}
+
+	return changelog
+}
+
+func parseChangelogEntry(commit LogEntry, rules *CommitRulesDTO) ChangelogEntry {
+	header, breaking := cutBreakingMark(commit.Subject, rules)
+	message := ParseCommitMessage(header, rules)
+
+	entry := ChangelogEntry{
+		Hash:        commit.Hash,
+		ShortHash:   commit.ShortHash,
+		Type:        message.Type,
+		Scope:       message.Scope,
+		Description: message.Description,
+		Breaking:    breaking,
+	}
+
+	if note, ok := breakingChangeNote(commit.Body); ok {
+		entry.Breaking = true
+		entry.BreakingNote = note
+	}
+
+	// The mark is only part of the grammar on headers that follow the template.
+	if message.Type == "" && len(rules.CommitTypeDTOs) > 0 {
+		entry.Description = commit.Subject
+		entry.Breaking = entry.BreakingNote != ""
+	}
+
+	return entry
+}
+
+// cutBreakingMark removes the ! that marks a breaking change, written between the type and scope and
+// the separator the template places before the description, e.g. feat(api)!: drop v1 or :boom: feat!: drop v1.
+func cutBreakingMark(header string, rules *CommitRulesDTO) (string, bool) {
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	re, err := templateRegexp(template)
+	if err != nil {
+		return header, false
+	}
+	description := re.SubexpIndex("description")
+
+	for index, r := range header {
+		if r != '!' {
+			continue
+		}
+
+		// Without the mark the header follows the template and the mark was before the description.
+		candidate := header[:index] + header[index+1:]
+		match := re.FindStringSubmatchIndex(candidate)
+		if match != nil && (description < 0 || match[2*description] >= index) {
+			return candidate, true
+		}
+	}
+
+	return header, false
+}
+
+// breakingChangeNote returns the text of the BREAKING CHANGE footer of body, up to the next blank line.
+func breakingChangeNote(body string) (string, bool) {
+	lines := strings.Split(body, "\n")
+
+	for i, line := range lines {
+		for _, footer := range breakingChangeFooters {
+			note, found := strings.CutPrefix(line, footer)
+			if !found {
+				continue
+			}
+
+			noteLines := []string{strings.TrimSpace(note)}
+			for _, next := range lines[i+1:] {
+				if strings.TrimSpace(next) == "" {
+					break
+				}
+				noteLines = append(noteLines, strings.TrimSpace(next))
+			}
+			return strings.TrimSpace(strings.Join(noteLines, "\n")), true
+		}
+	}
+
+	return "", false
+}
+
+// groupByScope keeps the order of the entries within each scope, entries without a scope come first.
+func groupByScope(entries []ChangelogEntry) []ChangelogScope {
+	var scopes []ChangelogScope
+
+	for _, entry := range entries {
+		index := slices.IndexFunc(scopes, func(s ChangelogScope) bool { return s.Scope == entry.Scope })
+		if index < 0 {
+			scopes = append(scopes, ChangelogScope{Scope: entry.Scope})
+			index = len(scopes) - 1
+		}
+		scopes[index].Entries = append(scopes[index].Entries, entry)
+	}
+
+	slices.SortStableFunc(scopes, func(a, b ChangelogScope) int {
+		return cmp.Compare(a.Scope, b.Scope)
+	})
+	return scopes
+}
+
+// changelogTitle is the section title of commitType: the one configured, its description or the type itself.
+func (rules *CommitRulesDTO) changelogTitle(commitType string) string {
+	if rules.Changelog != nil && rules.Changelog.Sections[commitType] != "" {
+		return rules.Changelog.Sections[commitType]
+	}
+
+	if commitType == "" {
+		if len(rules.CommitTypeDTOs) == 0 {
+			return "Changes"
+		}
+		return "Other changes"
+	}
+
+	return cmp.Or(strings.TrimSuffix(rules.findType(commitType).Description, "."), commitType)
+}
+
+// Markdown renders the changelog with the breaking changes first, then a section per commit type
+// with a subsection per scope.
+func (c Changelog) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# Changelog")
+	if c.Range != "" {
+		fmt.Fprintf(&sb, " %s", c.Range)
+	}
+	sb.WriteString("\n")
+
+	if len(c.Sections) == 0 {
+		sb.WriteString("\nNo changes.\n")
+		return sb.String()
+	}
+
+	if len(c.Breaking) > 0 {
+		sb.WriteString("\n## ⚠ Breaking changes\n\n")
+		for _, entry := range c.Breaking {
+			scope := ""
+			if entry.Scope != "" {
+				scope = fmt.Sprintf("**%s:** ", entry.Scope)
+			}
+			fmt.Fprintf(&sb, "- %s%s (%s)\n", scope, entry.Description, entry.ShortHash)
+
+			if entry.BreakingNote != "" {
+				for _, line := range strings.Split(entry.BreakingNote, "\n") {
+					fmt.Fprintf(&sb, "  %s\n", line)
+				}
+			}
+		}
+	}
+
+	for _, section := range c.Sections {
+		fmt.Fprintf(&sb, "\n## %s\n", section.Title)
+
+		for _, scope := range section.Scopes {
+			if scope.Scope != "" {
+				fmt.Fprintf(&sb, "\n### %s\n", scope.Scope)
+			}
+			sb.WriteString("\n")
+
+			for _, entry := range scope.Entries {
+				mark := ""
+				if entry.Breaking {
+					mark = "⚠ "
+				}
+				fmt.Fprintf(&sb, "- %s%s (%s)\n", mark, entry.Description, entry.ShortHash)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// JSON renders the changelog as indented JSON.
+func (c Changelog) JSON() (string, error) {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("JSON -> %v", err)
+	}
+	return string(content), nil
+}
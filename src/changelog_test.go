@@ -0,0 +1,168 @@
+package src
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testChangelogCommits() []LogEntry {
+	return []LogEntry{
+		{Hash: "a1", ShortHash: "a1", Subject: "fix(cache): handle nil entries"},
+		{Hash: "b2", ShortHash: "b2", Subject: "feat(api)!: drop the v1 endpoints"},
+		{Hash: "c3", ShortHash: "c3", Subject: "Update README"},
+		{Hash: "d4", ShortHash: "d4", Subject: "feat: add eviction", Body: "Old entries are removed.\n\nBREAKING CHANGE: the cache is bounded,\nset maxEntries to keep more."},
+		{Hash: "e5", ShortHash: "e5", Subject: "feat(cache): add metrics"},
+	}
+}
+
+func TestBuildChangelog(t *testing.T) {
+	changelog := BuildChangelog("v1.0.0..HEAD", testChangelogCommits(), DefaultRules())
+
+	var titles []string
+	for _, section := range changelog.Sections {
+		titles = append(titles, section.Title)
+	}
+
+	expected := []string{"Adds a new feature to the project", "Fixes a bug in the code", "Other changes"}
+	if !reflect.DeepEqual(titles, expected) {
+		t.Fatalf("expected sections %v, got %v", expected, titles)
+	}
+
+	var scopes []string
+	for _, scope := range changelog.Sections[0].Scopes {
+		scopes = append(scopes, scope.Scope)
+	}
+
+	if !reflect.DeepEqual(scopes, []string{"", "api", "cache"}) {
+		t.Errorf("expected the feat scopes sorted, got %v", scopes)
+	}
+
+	if len(changelog.Breaking) != 2 || changelog.Breaking[0].Description != "drop the v1 endpoints" {
+		t.Fatalf("expected two breaking changes, got %+v", changelog.Breaking)
+	}
+
+	if note := changelog.Breaking[1].BreakingNote; note != "the cache is bounded,\nset maxEntries to keep more." {
+		t.Errorf("unexpected breaking note %q", note)
+	}
+
+	if other := changelog.Sections[2].Scopes[0].Entries[0]; other.Description != "Update README" || other.Breaking {
+		t.Errorf("expected the subject kept on other changes, got %+v", other)
+	}
+}
+
+func TestBuildChangelogSectionTitles(t *testing.T) {
+	rules := DefaultRules().Extend(&CommitRulesDTO{
+		Changelog: &ChangelogRulesDTO{Sections: map[string]string{"feat": "Features", "": "Misc"}},
+	})
+
+	changelog := BuildChangelog("", testChangelogCommits(), rules)
+
+	if first, last := changelog.Sections[0].Title, changelog.Sections[2].Title; first != "Features" || last != "Misc" {
+		t.Errorf("expected the configured titles, got %s and %s", first, last)
+	}
+
+	changelog = BuildChangelog("", []LogEntry{{ShortHash: "a1", Subject: "net: fix a leak"}}, LinuxKernelRules())
+
+	if section := changelog.Sections[0]; section.Title != "Changes" || section.Scopes[0].Scope != "net" {
+		t.Errorf("expected a changes section by subsystem, got %+v", section)
+	}
+}
+
+func TestCutBreakingMark(t *testing.T) {
+	codeRules := &CommitRulesDTO{HeaderTemplate: "{code} {type}({scope}): {description}", CommitTypeDTOs: GitmojiRules().CommitTypeDTOs}
+
+	tests := []struct {
+		rules    *CommitRulesDTO
+		header   string
+		expected string
+		breaking bool
+	}{
+		{DefaultRules(), "feat(api)!: drop v1", "feat(api): drop v1", true},
+		{DefaultRules(), "feat!: drop v1", "feat: drop v1", true},
+		{DefaultRules(), "feat: wow!: drop v1", "feat: wow!: drop v1", false},
+		{DefaultRules(), "feat: drop v1!", "feat: drop v1!", false},
+		{codeRules, ":sparkles: feat!: drop v1", ":sparkles: feat: drop v1", true},
+		{codeRules, ":sparkles: feat(api)!: drop v1", ":sparkles: feat(api): drop v1", true},
+		{GitmojiRules(), "✨ feat!: drop v1", "✨ feat: drop v1", true},
+		{LinuxKernelRules(), "net!: drop the old socket option", "net: drop the old socket option", true},
+	}
+
+	for _, test := range tests {
+		header, breaking := cutBreakingMark(test.header, test.rules)
+		if header != test.expected || breaking != test.breaking {
+			t.Errorf("expected %q, %v for %q, got %q, %v", test.expected, test.breaking, test.header, header, breaking)
+		}
+	}
+
+	changelog := BuildChangelog("", []LogEntry{{ShortHash: "a1", Subject: ":sparkles: feat!: drop v1"}}, codeRules)
+	if len(changelog.Breaking) != 1 || changelog.Breaking[0].Type != "feat" {
+		t.Errorf("expected a breaking feat, got %+v", changelog)
+	}
+}
+
+func TestChangelogMarkdown(t *testing.T) {
+	markdown := BuildChangelog("v1.0.0..HEAD", testChangelogCommits(), DefaultRules()).Markdown()
+
+	expected := strings.Join([]string{
+		"# Changelog v1.0.0..HEAD",
+		"",
+		"## ⚠ Breaking changes",
+		"",
+		"- **api:** drop the v1 endpoints (b2)",
+		"- add eviction (d4)",
+		"  the cache is bounded,",
+		"  set maxEntries to keep more.",
+		"",
+		"## Adds a new feature to the project",
+		"",
+		"- ⚠ add eviction (d4)",
+		"",
+		"### api",
+		"",
+		"- ⚠ drop the v1 endpoints (b2)",
+		"",
+		"### cache",
+		"",
+		"- add metrics (e5)",
+		"",
+		"## Fixes a bug in the code",
+		"",
+		"### cache",
+		"",
+		"- handle nil entries (a1)",
+		"",
+		"## Other changes",
+		"",
+		"- Update README (c3)",
+	}, "\n") + "\n"
+
+	if markdown != expected {
+		t.Errorf("unexpected markdown:\n%s", markdown)
+	}
+
+	if empty := BuildChangelog("HEAD", nil, DefaultRules()).Markdown(); empty != "# Changelog HEAD\n\nNo changes.\n" {
+		t.Errorf("unexpected empty changelog %q", empty)
+	}
+}
+
+func TestChangelogJSON(t *testing.T) {
+	content, err := BuildChangelog("HEAD", testChangelogCommits()[:1], DefaultRules()).JSON()
+	if err != nil {
+		t.Fatalf("failed to write JSON: %v", err)
+	}
+
+	var changelog Changelog
+	if err := json.Unmarshal([]byte(content), &changelog); err != nil {
+		t.Fatalf("failed to read JSON back: %v", err)
+	}
+
+	if entry := changelog.Sections[0].Scopes[0].Entries[0]; entry.Type != "fix" || entry.Scope != "cache" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if !strings.Contains(content, `"breaking": []`) {
+		t.Errorf("expected an empty list of breaking changes, got %s", content)
+	}
+}
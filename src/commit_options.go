@@ -0,0 +1,134 @@
+package src
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Merge returns the options with the values set on other taking precedence.
+// It is used to apply command line flags over the .kcommitrc defaults.
+func (o CommitOptionsDTO) Merge(other CommitOptionsDTO) CommitOptionsDTO {
+	o.NoVerify = cmp.Or(other.NoVerify, o.NoVerify)
+	o.GpgSign = cmp.Or(other.GpgSign, o.GpgSign)
+	o.Signoff = cmp.Or(other.Signoff, o.Signoff)
+	o.AllowEmpty = cmp.Or(other.AllowEmpty, o.AllowEmpty)
+	o.All = cmp.Or(other.All, o.All)
+	o.SigningKey = cmp.Or(other.SigningKey, o.SigningKey)
+	o.Author = cmp.Or(other.Author, o.Author)
+	o.Date = cmp.Or(other.Date, o.Date)
+
+	return o
+}
+
+// Args converts the options to git commit arguments.
+func (o CommitOptionsDTO) Args() []string {
+	var args []string
+
+	if enabled(o.All) {
+		args = append(args, "--all")
+	}
+	if enabled(o.NoVerify) {
+		args = append(args, "--no-verify")
+	}
+	// A signing key signs unless signing is turned off explicitly.
+	if o.SigningKey != "" && (o.GpgSign == nil || *o.GpgSign) {
+		args = append(args, "--gpg-sign="+o.SigningKey)
+	} else if enabled(o.GpgSign) {
+		args = append(args, "--gpg-sign")
+	}
+	if enabled(o.Signoff) {
+		args = append(args, "--signoff")
+	}
+	if enabled(o.AllowEmpty) {
+		args = append(args, "--allow-empty")
+	}
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if o.Date != "" {
+		args = append(args, "--date="+o.Date)
+	}
+
+	return args
+}
+
+func enabled(value *bool) bool {
+	return value != nil && *value
+}
+
+// switchFlag is a boolean flag that is left nil when it is not given.
+type switchFlag struct {
+	value **bool
+}
+
+func (f switchFlag) IsBoolFlag() bool { return true }
+
+func (f switchFlag) String() string { return "" }
+
+func (f switchFlag) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*f.value = &parsed
+	return nil
+}
+
+// gpgSignFlag behaves like git's -S/--gpg-sign, it may be used alone or with a key id.
+type gpgSignFlag struct {
+	options *CommitOptionsDTO
+}
+
+func (f gpgSignFlag) IsBoolFlag() bool { return true }
+
+func (f gpgSignFlag) String() string { return "" }
+
+func (f gpgSignFlag) Set(value string) error {
+	gpgSign := value != "false"
+	f.options.GpgSign = &gpgSign
+	if value != "true" && value != "false" {
+		f.options.SigningKey = value
+	}
+	return nil
+}
+
+// ParseCommandLine reads the flags given after the command: the git commit options forwarded by kcommit
+// and the ones of kcommit itself.
+func ParseCommandLine(args []string, output io.Writer) (CommandLineDTO, error) {
+	commandLine := CommandLineDTO{}
+	options := &commandLine.CommitOptions
+
+	fs := flag.NewFlagSet("kc", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	for _, name := range []string{"no-verify", "n"} {
+		fs.Var(switchFlag{&options.NoVerify}, name, "bypass pre-commit and commit-msg hooks")
+	}
+	for _, name := range []string{"signoff", "s"} {
+		fs.Var(switchFlag{&options.Signoff}, name, "add a Signed-off-by trailer")
+	}
+	for _, name := range []string{"all", "a"} {
+		fs.Var(switchFlag{&options.All}, name, "stage modified and deleted files before committing")
+	}
+	for _, name := range []string{"gpg-sign", "S"} {
+		fs.Var(gpgSignFlag{options: options}, name, "GPG-sign the commit, optionally with the given `keyid`")
+	}
+	fs.Var(switchFlag{&options.AllowEmpty}, "allow-empty", "allow a commit without changes")
+	fs.StringVar(&options.Author, "author", "", "override the commit `author`")
+	fs.StringVar(&options.Date, "date", "", "override the author `date`")
+	fs.BoolVar(&commandLine.Plain, "plain", false, "ask with numbered choices read line by line instead of the interactive screens")
+	fs.BoolVar(&commandLine.JSON, "json", false, "print the changelog as JSON instead of Markdown")
+
+	if err := fs.Parse(args); err != nil {
+		return commandLine, fmt.Errorf("ParseCommandLine -> %w", err)
+	}
+
+	if fs.NArg() > 0 {
+		return commandLine, fmt.Errorf("ParseCommandLine -> unexpected argument %s", fs.Arg(0))
+	}
+
+	return commandLine, nil
+}
@@ -0,0 +1,67 @@
+package src
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{}, nil},
+		{[]string{"-n", "-s", "-a"}, []string{"--all", "--no-verify", "--signoff"}},
+		{[]string{"--gpg-sign", "--allow-empty"}, []string{"--gpg-sign", "--allow-empty"}},
+		{[]string{"-S=ABCDEF"}, []string{"--gpg-sign=ABCDEF"}},
+		{[]string{"--author", "Jane <jane@example.com>", "--date=now"}, []string{"--author=Jane <jane@example.com>", "--date=now"}},
+	}
+
+	for _, test := range tests {
+		commandLine, err := ParseCommandLine(test.args, io.Discard)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", test.args, err)
+		}
+
+		if got := commandLine.CommitOptions.Args(); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("expected args %v for %v, got %v", test.expected, test.args, got)
+		}
+	}
+
+	if _, err := ParseCommandLine([]string{"--unknown"}, io.Discard); err == nil {
+		t.Errorf("expected error for unknown flag")
+	}
+
+	commandLine, err := ParseCommandLine([]string{"--plain", "-n"}, io.Discard)
+	if err != nil || !commandLine.Plain || !enabled(commandLine.CommitOptions.NoVerify) {
+		t.Errorf("expected plain prompts with --no-verify, got %+v, %v", commandLine, err)
+	}
+
+	if commandLine, err := ParseCommandLine([]string{"--json"}, io.Discard); err != nil || !commandLine.JSON {
+		t.Errorf("expected JSON output, got %+v, %v", commandLine, err)
+	}
+}
+
+func TestCommitOptionsMerge(t *testing.T) {
+	on := true
+
+	config := CommitOptionsDTO{Signoff: &on, Author: "Config <config@example.com>"}
+	cli := CommitOptionsDTO{NoVerify: &on, Author: "Cli <cli@example.com>"}
+
+	expected := []string{"--no-verify", "--signoff", "--author=Cli <cli@example.com>"}
+	if got := config.Merge(cli).Args(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected args %v, got %v", expected, got)
+	}
+
+	// An explicit false on the command line turns off the .kcommitrc defaults.
+	config = CommitOptionsDTO{NoVerify: &on, Signoff: &on, SigningKey: "ABCDEF"}
+	commandLine, err := ParseCommandLine([]string{"--no-verify=false", "-s=false", "--gpg-sign=false"}, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := config.Merge(commandLine.CommitOptions).Args(); got != nil {
+		t.Errorf("expected no args, got %v", got)
+	}
+}
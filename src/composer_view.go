@@ -0,0 +1,424 @@
+package src
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ComposerPane is one of the parts of the composer, in the order they are visited with tab.
+type ComposerPane int
+
+const (
+	TypePane ComposerPane = iota
+	ScopePane
+	DescriptionPane
+	BodyPane
+	ConfirmPane
+)
+
+// ComposerOptions configures the composer.
+type ComposerOptions struct {
+	Title string
+	// Types are the commit types to choose from.
+	Types []ListItem
+	// Message pre-fills the composer, its type is selected when it is one of Types.
+	Message CommitMessage
+	// Rules limit, validate and preview the description.
+	Rules *CommitRulesDTO
+	// Actions are the choices of the confirm step, e.g. commit or just print.
+	Actions []ListItem
+	// Focus is the pane focused when the composer opens.
+	Focus ComposerPane
+	// ScopeSuggestions complete the scope with tab, e.g. the scopes used before on the project.
+	ScopeSuggestions []string
+	// DescriptionSuggestions complete the description with tab, e.g. the descriptions of recent commits.
+	DescriptionSuggestions []string
+}
+
+// ComposerResult is the message written on the composer.
+// Action is the chosen action, ExitSignal when cancelled, BackSignal to go back to the previous prompt
+// or EditorSignal when the editor was asked for.
+type ComposerResult struct {
+	Message CommitMessage
+	Action  string
+}
+
+// typeListHeight is how many types are shown at once while the type pane is focused.
+const typeListHeight = 6
+
+type composerModel struct {
+	options ComposerOptions
+	focus   ComposerPane
+
+	typeCursor   int
+	actionCursor int
+
+	scope       textinput.Model
+	description textinput.Model
+	body        textarea.Model
+
+	problem  string
+	result   *ComposerResult
+	quitting bool
+	styles   *Styles
+}
+
+func ComposerViewModel(options ComposerOptions, result *ComposerResult) composerModel {
+	styles := DefaultStyles()
+
+	if options.Rules == nil {
+		options.Rules = DefaultRules()
+	}
+
+	// Values are set before the limits, SetValue cuts what goes over them. Pre-filled values, e.g. from
+	// kc amend or the editor, are kept whole and checked when the message is confirmed.
+	scope := textinput.New()
+	scope.SetValue(options.Message.Scope)
+	scope.Placeholder = "scope"
+	scope.CharLimit = 64
+	scope.ShowSuggestions = true
+	scope.SetSuggestions(options.ScopeSuggestions)
+
+	description := textinput.New()
+	description.SetValue(options.Message.Description)
+	description.Placeholder = "description"
+	description.ShowSuggestions = true
+
+	body := textarea.New()
+	body.CharLimit = 0
+	body.SetValue(options.Message.Body)
+	body.Placeholder = "Optional, explain what changed and why"
+	body.ShowLineNumbers = false
+	body.SetWidth(76)
+	body.SetHeight(4)
+
+	m := composerModel{
+		options:     options,
+		scope:       scope,
+		description: description,
+		body:        body,
+		result:      result,
+		styles:      styles,
+	}
+
+	for index, t := range options.Types {
+		if t.T == options.Message.Type {
+			m.typeCursor = index
+			break
+		}
+	}
+
+	// Without types, e.g. `subsystem: description` headers, there is nothing to choose on the type pane.
+	if len(options.Types) == 0 && options.Focus == TypePane {
+		m.options.Focus = ScopePane
+	}
+
+	m.updateDescriptionLimit()
+	m.setFocus(m.options.Focus)
+
+	return m
+}
+
+func (m composerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// message is what was written so far.
+func (m composerModel) message() CommitMessage {
+	message := m.options.Message
+	message.Type = ""
+	if len(m.options.Types) > 0 {
+		message.Type = m.options.Types[m.typeCursor].T
+	}
+	message.Scope = strings.TrimSpace(m.scope.Value())
+	message.Description = strings.TrimSpace(m.description.Value())
+	message.Body = strings.TrimSpace(m.body.Value())
+	return message
+}
+
+// updateDescriptionLimit follows the type and scope, so the header stays within its limit.
+// Suggestions that do not fit are left out.
+func (m *composerModel) updateDescriptionLimit() {
+	limit := m.options.Rules.DescriptionLimit(m.message())
+	m.description.CharLimit = limit
+
+	var suggestions []string
+	for _, suggestion := range m.options.DescriptionSuggestions {
+		if len([]rune(suggestion)) <= limit {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	m.description.SetSuggestions(suggestions)
+}
+
+// completes tells whether tab completes the value of the input with a suggestion instead of moving on.
+func completes(input textinput.Model) bool {
+	return len([]rune(input.CurrentSuggestion())) > len([]rune(input.Value()))
+}
+
+func (m *composerModel) setFocus(pane ComposerPane) {
+	m.focus = pane
+
+	m.scope.Blur()
+	m.description.Blur()
+	m.body.Blur()
+
+	switch pane {
+	case ScopePane:
+		m.scope.Focus()
+	case DescriptionPane:
+		m.description.Focus()
+	case BodyPane:
+		m.body.Focus()
+	}
+}
+
+func (m *composerModel) move(offset int) {
+	pane := min(max(int(m.focus)+offset, int(TypePane)), int(ConfirmPane))
+	m.setFocus(ComposerPane(pane))
+	m.updateDescriptionLimit()
+}
+
+func (m composerModel) quit(action string) (tea.Model, tea.Cmd) {
+	*m.result = ComposerResult{Message: m.message(), Action: action}
+	m.quitting = true
+	return m, tea.Quit
+}
+
+func (m composerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m.quit(ExitSignal)
+
+		case "ctrl+e":
+			return m.quit(EditorSignal)
+
+		case "tab":
+			if (m.focus == ScopePane && completes(m.scope)) || (m.focus == DescriptionPane && completes(m.description)) {
+				break
+			}
+			m.move(1)
+			return m, nil
+
+		case "shift+tab":
+			// The type is the first pane, from there it goes back to the previous prompt.
+			if m.focus == TypePane || (m.focus == ScopePane && len(m.options.Types) == 0) {
+				return m.quit(BackSignal)
+			}
+			m.move(-1)
+			return m, nil
+		}
+
+		switch m.focus {
+		case TypePane, ConfirmPane:
+			return m.updateChoices(msg)
+
+		case ScopePane, DescriptionPane:
+			if msg.Type == tea.KeyEnter {
+				if m.focus == DescriptionPane && !m.check() {
+					return m, nil
+				}
+				m.move(1)
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+
+	switch m.focus {
+	case ScopePane:
+		m.scope, cmd = m.scope.Update(msg)
+		m.updateDescriptionLimit()
+	case DescriptionPane:
+		m.description, cmd = m.description.Update(msg)
+		// Once an error is shown it follows what is typed, so it goes away when fixed.
+		if m.problem != "" {
+			m.check()
+		}
+	case BodyPane:
+		m.body, cmd = m.body.Update(msg)
+	}
+
+	return m, cmd
+}
+
+// updateChoices moves the cursor of the type list and of the confirm step.
+func (m composerModel) updateChoices(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cursor, count := &m.typeCursor, len(m.options.Types)
+	if m.focus == ConfirmPane {
+		cursor, count = &m.actionCursor, len(m.options.Actions)
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		*cursor = max(*cursor-1, 0)
+
+	case "down", "j":
+		*cursor = min(*cursor+1, max(count-1, 0))
+
+	case "enter":
+		if m.focus == TypePane {
+			m.move(1)
+			return m, nil
+		}
+
+		if !m.check() {
+			m.setFocus(DescriptionPane)
+			return m, nil
+		}
+
+		if err := m.message().Validate(m.options.Rules); errors.Is(err, ErrScopeRequired) {
+			m.problem = err.Error()
+			m.setFocus(ScopePane)
+			return m, nil
+		}
+
+		if count == 0 {
+			return m.quit("")
+		}
+		return m.quit(m.options.Actions[*cursor].T)
+	}
+
+	m.updateDescriptionLimit()
+	return m, nil
+}
+
+// check validates the description and the length of the header it makes, the problem is shown under it.
+func (m *composerModel) check() bool {
+	m.problem = ""
+
+	message := m.message()
+	if err := m.options.Rules.ValidateDescription(message.Description); err != nil {
+		m.problem = err.Error()
+	} else if limit := m.options.Rules.DescriptionLimit(message); len([]rune(message.Description)) > limit {
+		m.problem = fmt.Sprintf("the description can have at most %d characters", limit)
+	}
+	return m.problem == ""
+}
+
+func (m composerModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	message := m.message()
+
+	sections := []string{
+		m.styles.TitleStyle.Render(fmt.Sprintf("\n%s\n", m.options.Title)),
+		m.pane(TypePane, "Type", m.typeView()),
+		m.pane(ScopePane, "Scope", m.field(ScopePane, m.scope.View())),
+		m.pane(DescriptionPane, "Description", m.descriptionView(message)),
+		m.pane(BodyPane, "Body", m.field(BodyPane, m.body.View())),
+		m.pane(ConfirmPane, "Confirm", m.confirmView(message)),
+		m.styles.FooterStyle.Render("\n(tab/shift+tab to move, tab completes a suggestion, shift+tab on the type to go back, enter to continue, ctrl+e to open the editor, ctrl+c or esc to quit)"),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// pane renders a pane with its label, the label of the focused pane is highlighted.
+func (m composerModel) pane(pane ComposerPane, label string, content string) string {
+	style := m.styles.ComposerLabelStyle
+	if m.focus == pane {
+		style = m.styles.ComposerFocusedLabelStyle
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, style.Render(label), content) + "\n"
+}
+
+func (m composerModel) field(pane ComposerPane, content string) string {
+	if m.focus == pane {
+		return m.styles.ComposerFocusedFieldStyle.Render(content)
+	}
+	return m.styles.ComposerFieldStyle.Render(content)
+}
+
+// typeView shows only the chosen type, the list is shown while the pane is focused.
+func (m composerModel) typeView() string {
+	if len(m.options.Types) == 0 {
+		return ""
+	}
+
+	if m.focus != TypePane {
+		t := m.options.Types[m.typeCursor]
+		return fmt.Sprintf("%s  %s", t.T, m.styles.Text(t.D, m.styles.FooterColor))
+	}
+
+	start := min(max(m.typeCursor-typeListHeight/2, 0), max(len(m.options.Types)-typeListHeight, 0))
+	end := min(start+typeListHeight, len(m.options.Types))
+
+	var lines []string
+	for index := start; index < end; index++ {
+		lines = append(lines, m.choice(m.options.Types[index], index == m.typeCursor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m composerModel) descriptionView(message CommitMessage) string {
+	header := message.Header(m.options.Rules)
+	length, limit := len([]rune(header)), m.options.Rules.Description.maxHeaderLength()
+
+	count := m.styles.Text(fmt.Sprintf("%d/%d", length, limit), headerLengthColor(m.styles, length, limit))
+
+	lines := []string{
+		m.field(DescriptionPane, m.description.View()),
+		m.styles.PreviewStyle.Render(fmt.Sprintf("%s  %s", header, count)),
+	}
+
+	if m.problem != "" {
+		lines = append(lines, m.styles.ErrorStyle.Render(m.problem))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// headerLengthColor highlights the header length once it uses 80% of the limit and again when it reaches it.
+func headerLengthColor(styles *Styles, length, limit int) lipgloss.Color {
+	switch {
+	case length >= limit:
+		return styles.ErrorColor
+	case length*5 >= limit*4:
+		return styles.PeachColor
+	}
+	return styles.AquamarineColor
+}
+
+// confirmView shows the message that will be used and the actions to choose from.
+func (m composerModel) confirmView(message CommitMessage) string {
+	if m.focus != ConfirmPane {
+		return m.styles.Text("tab to review the message", m.styles.FooterColor)
+	}
+
+	lines := []string{m.styles.PreviewStyle.Render(message.Format(m.options.Rules)), ""}
+	for index, action := range m.options.Actions {
+		lines = append(lines, m.choice(action, index == m.actionCursor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m composerModel) choice(item ListItem, selected bool) string {
+	if selected {
+		return m.styles.Text(fmt.Sprintf("> %s  %s", item.T, item.D), m.styles.SelectedTitleColor)
+	}
+	return fmt.Sprintf("  %s  %s", item.T, m.styles.Text(item.D, m.styles.FooterColor))
+}
+
+// ComposerView shows type, scope, description and body on a single screen.
+func ComposerView(options ComposerOptions) ComposerResult {
+	result := ComposerResult{}
+
+	if _, err := tea.NewProgram(ComposerViewModel(options, &result)).Run(); err != nil {
+		fmt.Println("ComposerView -> ", err)
+		os.Exit(1)
+	}
+
+	return result
+}
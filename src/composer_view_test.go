@@ -0,0 +1,217 @@
+package src
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testComposerOptions() ComposerOptions {
+	return ComposerOptions{
+		Title:   "Write the commit message",
+		Types:   []ListItem{{T: "feat", D: "A new feature"}, {T: "fix", D: "A bug fix"}},
+		Message: CommitMessage{Type: "fix", Scope: "cache"},
+		Rules:   DefaultRules(),
+		Actions: []ListItem{{T: "commit"}, {T: "just print"}},
+	}
+}
+
+func updateComposer(t *testing.T, model tea.Model, keys ...tea.KeyMsg) tea.Model {
+	t.Helper()
+	for _, key := range keys {
+		model, _ = model.Update(key)
+	}
+	return model
+}
+
+func typeText(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+func TestComposerViewModelWritesMessage(t *testing.T) {
+	result := ComposerResult{}
+	model := tea.Model(ComposerViewModel(testComposerOptions(), &result))
+
+	model = updateComposer(t, model,
+		// The type of the message is pre-selected, up goes back to feat.
+		tea.KeyMsg{Type: tea.KeyUp},
+		tea.KeyMsg{Type: tea.KeyEnter},
+		// The scope is kept.
+		tea.KeyMsg{Type: tea.KeyEnter},
+		typeText("add eviction"),
+		tea.KeyMsg{Type: tea.KeyEnter},
+		typeText("Old entries are removed."),
+		tea.KeyMsg{Type: tea.KeyTab},
+		tea.KeyMsg{Type: tea.KeyDown},
+	)
+
+	if view := model.View(); !strings.Contains(view, "Confirm       feat(cache): add eviction") || !strings.Contains(view, "> just print") {
+		t.Errorf("expected the confirm step to show the message, got %q", view)
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected the composer to quit on confirm")
+	}
+
+	expected := CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction", Body: "Old entries are removed."}
+	if result.Message != expected || result.Action != "just print" {
+		t.Errorf("expected %+v with just print, got %+v", expected, result)
+	}
+}
+
+func TestComposerViewModelGoesBackToChangeType(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Message.Description = "handle nil entries"
+	options.Focus = DescriptionPane
+
+	model := updateComposer(t, ComposerViewModel(options, &result),
+		tea.KeyMsg{Type: tea.KeyShiftTab},
+		tea.KeyMsg{Type: tea.KeyShiftTab},
+		tea.KeyMsg{Type: tea.KeyUp},
+		tea.KeyMsg{Type: tea.KeyCtrlC},
+	)
+
+	if model.View() != "" {
+		t.Errorf("expected empty view after cancel")
+	}
+
+	if result.Action != ExitSignal || result.Message.Type != "feat" || result.Message.Description != "handle nil entries" {
+		t.Errorf("expected the changed type and the description to be kept on cancel, got %+v", result)
+	}
+}
+
+func TestComposerViewModelValidatesDescription(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Rules = ConventionalRules()
+	options.Focus = DescriptionPane
+
+	model := updateComposer(t, ComposerViewModel(options, &result),
+		typeText("Added eviction"),
+		tea.KeyMsg{Type: tea.KeyEnter},
+	)
+
+	if view := model.View(); !strings.Contains(view, "must start with a lowercase letter") {
+		t.Errorf("expected the validation error under the description, got %q", view)
+	}
+
+	// The confirm step sends back to the description while it is invalid.
+	model = updateComposer(t, model, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.(composerModel).focus != DescriptionPane || result.Action != "" {
+		t.Errorf("expected the invalid description to be focused, got pane %d and %+v", model.(composerModel).focus, result)
+	}
+}
+
+func TestComposerViewModelLimitsDescription(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Rules.Description = &DescriptionRulesDTO{MaxHeaderLength: 20}
+	options.Focus = DescriptionPane
+
+	model := updateComposer(t, ComposerViewModel(options, &result), typeText("add a long description"))
+
+	if description := model.(composerModel).description.Value(); description != "add a lo" {
+		t.Errorf("expected the description to fit the header limit, got %q", description)
+	}
+
+	if view := model.View(); !strings.Contains(view, "fix(cache): add a lo  20/20") {
+		t.Errorf("expected the header preview with its length, got %q", view)
+	}
+}
+
+func TestComposerViewModelKeepsPrefilledMessage(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Message.Description = strings.Repeat("d", 120)
+	options.Message.Body = strings.Repeat("b", 599)
+	options.Focus = ConfirmPane
+
+	model := tea.Model(ComposerViewModel(options, &result))
+
+	if message := model.(composerModel).message(); message.Description != options.Message.Description || message.Body != options.Message.Body {
+		t.Fatalf("expected the pre-filled message to be kept whole, got %d and %d characters", len(message.Description), len(message.Body))
+	}
+
+	// The header over its limit is reported instead of being cut.
+	model = updateComposer(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.(composerModel).focus != DescriptionPane || result.Action != "" {
+		t.Errorf("expected the long description to be focused, got pane %d and %+v", model.(composerModel).focus, result)
+	}
+
+	if view := model.View(); !strings.Contains(view, "the description can have at most 88 characters") {
+		t.Errorf("expected the length error under the description, got %q", view)
+	}
+}
+
+func TestComposerViewModelOpensEditor(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Message.Description = "handle nil entries"
+
+	_, cmd := ComposerViewModel(options, &result).Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+
+	if cmd == nil || result.Action != EditorSignal || result.Message.Description != "handle nil entries" {
+		t.Errorf("expected the editor signal with the message, got %+v", result)
+	}
+}
+
+func TestComposerViewModelCompletesDescription(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Focus = DescriptionPane
+	options.DescriptionSuggestions = []string{"address review comments", "add eviction of old entries to the cache"}
+	options.Rules.Description = &DescriptionRulesDTO{MaxHeaderLength: 40}
+
+	model := updateComposer(t, ComposerViewModel(options, &result), typeText("add"), tea.KeyMsg{Type: tea.KeyTab})
+
+	// The suggestion too long for the header is left out.
+	if composer := model.(composerModel); composer.description.Value() != "address review comments" || composer.focus != DescriptionPane {
+		t.Errorf("expected tab to complete the description, got %q on pane %d", composer.description.Value(), composer.focus)
+	}
+
+	// Once completed tab moves on.
+	if model = updateComposer(t, model, tea.KeyMsg{Type: tea.KeyTab}); model.(composerModel).focus != BodyPane {
+		t.Errorf("expected tab to move to the body, got pane %d", model.(composerModel).focus)
+	}
+}
+
+func TestComposerViewModelCompletesScope(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Message.Scope = ""
+	options.Focus = ScopePane
+	options.ScopeSuggestions = []string{"authentication", "api"}
+
+	model := updateComposer(t, ComposerViewModel(options, &result), typeText("au"), tea.KeyMsg{Type: tea.KeyTab})
+
+	if composer := model.(composerModel); composer.scope.Value() != "authentication" || composer.focus != ScopePane {
+		t.Errorf("expected tab to complete the scope, got %q on pane %d", composer.scope.Value(), composer.focus)
+	}
+}
+
+func TestComposerViewModelWithoutTypes(t *testing.T) {
+	result := ComposerResult{}
+	options := testComposerOptions()
+	options.Types = nil
+	options.Rules = LinuxKernelRules()
+	options.Message = CommitMessage{Description: "fix a leak"}
+
+	model := tea.Model(ComposerViewModel(options, &result))
+	if focus := model.(composerModel).focus; focus != ScopePane {
+		t.Fatalf("expected the scope to be focused, got pane %d", focus)
+	}
+
+	// The subsystem is required before confirming.
+	model = updateComposer(t, model, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	if composer := model.(composerModel); composer.focus != ScopePane || composer.problem != ErrScopeRequired.Error() {
+		t.Errorf("expected the missing scope to be focused, got pane %d with %q", composer.focus, composer.problem)
+	}
+
+	model = updateComposer(t, model, typeText("mm"), tea.KeyMsg{Type: tea.KeyShiftTab})
+	if result.Action != BackSignal || result.Message.Header(options.Rules) != "mm: fix a leak" {
+		t.Errorf("expected back from the scope with the header, got %+v", result)
+	}
+}
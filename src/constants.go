@@ -2,7 +2,31 @@ package src
 
 const (
 	ExitSignal             = "__quit_kcommit__"
+	EditorSignal           = "__editor_kcommit__"
+	BackSignal             = "__back_kcommit__"
 	KcommitDirName         = ".kcommit"
 	KcommitRcFileName      = ".kcommitrc"
 	KcommitHistoryFileName = ".kcommit_history.json"
+	KcommitConfigFileName  = ".kcommit_config.json"
+	KcommitLastMessageName = ".kcommit_last_message"
+	KcommitCacheDirName    = "cache"
+
+	GitBackendEnv = "KCOMMIT_GIT_BACKEND"
+	GitBackend    = "git"
+	GoGitBackend  = "go-git"
+
+	AutoPrompts        = "auto"
+	InteractivePrompts = "interactive"
+	PlainPrompts       = "plain"
+
+	RecentCommitsLimit      = 20
+	RecentDescriptionsLimit = 50
+
+	DefaultEditor = "vi"
+
+	DefaultHeaderTemplate = "{type}({scope}): {description}"
+	DefaultTicketPattern  = `[A-Z][A-Z0-9]+-[0-9]+|#[0-9]+`
+	DefaultTicketTrailer  = "Refs: {ticket}"
+
+	DefaultMaxHeaderLength = 100
 )
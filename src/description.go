@@ -0,0 +1,143 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultNonImperativeWords are the first words that show a description is not in the imperative mood.
+var DefaultNonImperativeWords = []string{
+	"added", "adds", "adding",
+	"fixed", "fixes", "fixing",
+	"updated", "updates", "updating",
+	"removed", "removes", "removing",
+	"changed", "changes", "changing",
+	"created", "creates", "creating",
+	"deleted", "deletes", "deleting",
+	"improved", "improves", "improving",
+	"implemented", "implements", "implementing",
+	"refactored", "refactors", "refactoring",
+	"renamed", "renames", "renaming",
+	"moved", "moves", "moving",
+}
+
+func (d *DescriptionRulesDTO) maxHeaderLength() int {
+	if d == nil || d.MaxHeaderLength <= 0 {
+		return DefaultMaxHeaderLength
+	}
+	return d.MaxHeaderLength
+}
+
+func (d *DescriptionRulesDTO) lowercaseFirst() bool {
+	return d != nil && enabled(d.LowercaseFirst)
+}
+
+func (d *DescriptionRulesDTO) noTrailingPeriod() bool {
+	return d != nil && enabled(d.NoTrailingPeriod)
+}
+
+// nonImperativeWords are the words checked by the imperative mood check, none when it is off.
+// Setting a list turns the check on unless imperativeMood is false.
+func (d *DescriptionRulesDTO) nonImperativeWords() []string {
+	if d == nil || (d.ImperativeMood != nil && !*d.ImperativeMood) {
+		return nil
+	}
+	if len(d.NonImperativeWords) > 0 {
+		return d.NonImperativeWords
+	}
+	if enabled(d.ImperativeMood) {
+		return DefaultNonImperativeWords
+	}
+	return nil
+}
+
+func (d *DescriptionRulesDTO) forbiddenWords() []string {
+	if d == nil {
+		return nil
+	}
+	return d.ForbiddenWords
+}
+
+// extend returns the description rules with the fields set on other taking precedence.
+func (d *DescriptionRulesDTO) extend(other *DescriptionRulesDTO) *DescriptionRulesDTO {
+	if d == nil {
+		return other
+	}
+	if other == nil {
+		return d
+	}
+
+	extended := *d
+	if other.MaxHeaderLength != 0 {
+		extended.MaxHeaderLength = other.MaxHeaderLength
+	}
+	if other.LowercaseFirst != nil {
+		extended.LowercaseFirst = other.LowercaseFirst
+	}
+	if other.NoTrailingPeriod != nil {
+		extended.NoTrailingPeriod = other.NoTrailingPeriod
+	}
+	if other.ImperativeMood != nil {
+		extended.ImperativeMood = other.ImperativeMood
+	}
+	if other.NonImperativeWords != nil {
+		extended.NonImperativeWords = other.NonImperativeWords
+	}
+	if other.ForbiddenWords != nil {
+		extended.ForbiddenWords = other.ForbiddenWords
+	}
+	return &extended
+}
+
+// DescriptionLimit is how many characters the description may have so the header
+// of message stays within the configured max length.
+func (rules *CommitRulesDTO) DescriptionLimit(message CommitMessage) int {
+	// The description is measured with a single character, so templates with text around it are counted too.
+	message.Description = "x"
+	prefix := utf8.RuneCountInString(message.Header(rules)) - 1
+
+	return max(rules.Description.maxHeaderLength()-prefix, 1)
+}
+
+// ValidateDescription checks the description against the rules of the project.
+func (rules *CommitRulesDTO) ValidateDescription(description string) error {
+	d := rules.Description
+	description = strings.TrimSpace(description)
+
+	if description == "" {
+		return fmt.Errorf("the description is empty")
+	}
+
+	first, _ := utf8.DecodeRuneInString(description)
+	if d.lowercaseFirst() && unicode.IsUpper(first) {
+		return fmt.Errorf("the description must start with a lowercase letter")
+	}
+
+	if d.noTrailingPeriod() && strings.HasSuffix(description, ".") {
+		return fmt.Errorf("the description must not end with a period")
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' && r != '_'
+	})
+
+	if len(words) > 0 {
+		for _, word := range d.nonImperativeWords() {
+			if strings.EqualFold(words[0], word) {
+				return fmt.Errorf("use the imperative mood, e.g. \"add\" instead of %q", words[0])
+			}
+		}
+	}
+
+	for _, forbidden := range d.forbiddenWords() {
+		for _, word := range words {
+			if strings.EqualFold(word, forbidden) {
+				return fmt.Errorf("%q is not allowed in the description", forbidden)
+			}
+		}
+	}
+
+	return nil
+}
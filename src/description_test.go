@@ -0,0 +1,60 @@
+package src
+
+import "testing"
+
+func TestDescriptionLimit(t *testing.T) {
+	tests := []struct {
+		rules    CommitRulesDTO
+		message  CommitMessage
+		expected int
+	}{
+		{CommitRulesDTO{}, CommitMessage{Type: "feat", Scope: "cache"}, DefaultMaxHeaderLength - len("feat(cache): ")},
+		{CommitRulesDTO{Description: &DescriptionRulesDTO{MaxHeaderLength: 50}}, CommitMessage{Type: "fix"}, 50 - len("fix: ")},
+		{
+			CommitRulesDTO{HeaderTemplate: "{type}: {description} ({ticket})", Description: &DescriptionRulesDTO{MaxHeaderLength: 30}},
+			CommitMessage{Type: "fix", Ticket: "ABC-1"},
+			30 - len("fix:  (ABC-1)"),
+		},
+		{CommitRulesDTO{Description: &DescriptionRulesDTO{MaxHeaderLength: 5}}, CommitMessage{Type: "feat", Scope: "cache"}, 1},
+	}
+
+	for _, test := range tests {
+		if limit := test.rules.DescriptionLimit(test.message); limit != test.expected {
+			t.Errorf("expected limit %d for %+v, got %d", test.expected, test.message, limit)
+		}
+	}
+}
+
+func TestValidateDescription(t *testing.T) {
+	on, disabled := true, false
+	strict := strictDescriptionRules(100)
+
+	tests := []struct {
+		name        string
+		rules       *DescriptionRulesDTO
+		description string
+		valid       bool
+	}{
+		{"default rules", nil, "add eviction of old entries", true},
+		{"checks off by default", nil, "Updated README.", true},
+		{"empty", nil, "  ", false},
+		{"uppercase first letter", strict, "Add eviction", false},
+		{"uppercase allowed", &DescriptionRulesDTO{LowercaseFirst: &disabled}, "Add eviction", true},
+		{"trailing period", strict, "add eviction.", false},
+		{"trailing period allowed", &DescriptionRulesDTO{NoTrailingPeriod: &disabled}, "add eviction.", true},
+		{"not imperative", strict, "added eviction", false},
+		{"non imperative word later on", strict, "add eviction that fixes memory usage", true},
+		{"custom word list", &DescriptionRulesDTO{NonImperativeWords: []string{"adding"}}, "adding eviction", false},
+		{"custom word list replaces the default", &DescriptionRulesDTO{ImperativeMood: &on, NonImperativeWords: []string{"adding"}}, "added eviction", true},
+		{"imperative check disabled", strict.extend(&DescriptionRulesDTO{ImperativeMood: &disabled}), "fixes memory usage", true},
+		{"forbidden word", &DescriptionRulesDTO{ForbiddenWords: []string{"wip"}}, "add eviction WIP", false},
+		{"forbidden word as part of another", &DescriptionRulesDTO{ForbiddenWords: []string{"wip"}}, "wipe old entries", true},
+	}
+
+	for _, test := range tests {
+		rules := CommitRulesDTO{Description: test.rules}
+		if err := rules.ValidateDescription(test.description); (err == nil) != test.valid {
+			t.Errorf("%s: unexpected validation of %q: %v", test.name, test.description, err)
+		}
+	}
+}
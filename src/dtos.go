@@ -11,26 +11,127 @@ type HistoryDTO struct {
 type ProjectDTO struct {
 	Name     string      `json:"name"`
 	Branches []BranchDTO `json:"branches"`
+	// Descriptions are the last descriptions written on the project, the most recent first.
+	Descriptions []string `json:"descriptions,omitempty"`
 }
 
 type BranchDTO struct {
-	Name      string    `json:"name"`
-	Scope     string    `json:"scope"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Name   string    `json:"name"`
+	Scope  string    `json:"scope"`
+	Ticket string    `json:"ticket,omitempty"`
+	Draft  *DraftDTO `json:"draft,omitempty"`
+	// Types counts the commit types used on the branch, to rank them.
+	Types     []TypeUsageDTO `json:"types,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TypeUsageDTO is how many times a commit type was used and when it was last used.
+type TypeUsageDTO struct {
+	Type     string    `json:"type"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// DraftDTO is a commit message that was cancelled or failed to commit, kept to be resumed.
+type DraftDTO struct {
+	Type        string `json:"type"`
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+	Body        string `json:"body,omitempty"`
 }
 
 type CommitTypeDTO struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
+	// Emoji and Code, e.g. ✨ and :sparkles:, are shown in the type list and used by {emoji} and {code}.
+	Emoji string `json:"emoji,omitempty"`
+	Code  string `json:"code,omitempty"`
+}
+
+type TicketDTO struct {
+	Pattern string `json:"pattern"`
+	Trailer string `json:"trailer"`
+}
+
+// DescriptionRulesDTO configures how the description is validated.
+// The checks are off unless they are set here or by the preset.
+type DescriptionRulesDTO struct {
+	MaxHeaderLength  int   `json:"maxHeaderLength"`
+	LowercaseFirst   *bool `json:"lowercaseFirst"`
+	NoTrailingPeriod *bool `json:"noTrailingPeriod"`
+	// ImperativeMood rejects descriptions starting with one of NonImperativeWords,
+	// DefaultNonImperativeWords when there are none.
+	ImperativeMood     *bool    `json:"imperativeMood"`
+	NonImperativeWords []string `json:"nonImperativeWords"`
+	ForbiddenWords     []string `json:"forbiddenWords"`
+}
+
+// CommitOptionsDTO holds the git commit options. The switches are nil when they are not set,
+// so an explicit false on the command line overrides .kcommitrc.
+type CommitOptionsDTO struct {
+	NoVerify   *bool  `json:"noVerify"`
+	GpgSign    *bool  `json:"gpgSign"`
+	SigningKey string `json:"signingKey"`
+	Signoff    *bool  `json:"signoff"`
+	AllowEmpty *bool  `json:"allowEmpty"`
+	Author     string `json:"author"`
+	Date       string `json:"date"`
+	All        *bool  `json:"all"`
+}
+
+// CommandLineDTO holds the flags given after the command.
+type CommandLineDTO struct {
+	CommitOptions CommitOptionsDTO
+	// Plain asks with line-based prompts, see PlainViewBuilder.
+	Plain bool
+	// JSON prints kc changelog as JSON.
+	JSON bool
 }
 
 type CommitRulesDTO struct {
-	CommitTypeDTOs []CommitTypeDTO `json:"commitTypes"`
+	// Preset names the built-in rules the others extend, see Presets.
+	Preset string `json:"preset"`
+	// Extends is a path, relative to the repository root, or an HTTP(S) URL of shared rules these extend.
+	Extends string `json:"extends"`
+	// ExtendsChecksum pins the content of Extends, e.g. sha256:<hex>.
+	ExtendsChecksum string               `json:"extendsChecksum"`
+	CommitTypeDTOs  []CommitTypeDTO      `json:"commitTypes"`
+	HeaderTemplate  string               `json:"headerTemplate"`
+	Ticket          *TicketDTO           `json:"ticket"`
+	CommitOptions   *CommitOptionsDTO    `json:"commitOptions"`
+	Description     *DescriptionRulesDTO `json:"description"`
+	// Scopes are suggested when writing the scope, before the ones used on the project.
+	Scopes []string `json:"scopes"`
+	// PinTypeOrder keeps the commit types in the order above instead of ranking them by use.
+	PinTypeOrder bool `json:"pinTypeOrder"`
+	// Changelog configures kc changelog.
+	Changelog *ChangelogRulesDTO `json:"changelog"`
+}
+
+// ChangelogRulesDTO configures how kc changelog groups the commits.
+type ChangelogRulesDTO struct {
+	// Sections titles the section of each commit type, the type description is used otherwise.
+	Sections map[string]string `json:"sections"`
+}
+
+// UserConfigDTO holds the settings of ~/.kcommit/.kcommit_config.json, shared by every project.
+type UserConfigDTO struct {
+	GitBackend string    `json:"gitBackend"`
+	Theme      *ThemeDTO `json:"theme"`
+	// Prompts is auto, interactive or plain.
+	Prompts string `json:"prompts"`
+}
+
+// ThemeDTO picks one of the built-in themes and overrides some of its colors.
+type ThemeDTO struct {
+	Name   string            `json:"name"`
+	Colors map[string]string `json:"colors"`
 }
 
 func (dto *HistoryDTO) ToModel() History {
 	history := History{
-		Projects: make(map[string]map[string]BranchDetail),
+		Projects:     make(map[string]map[string]BranchDetail),
+		Descriptions: make(map[string][]string),
 	}
 
 	for _, project := range dto.Projects {
@@ -39,11 +140,18 @@ func (dto *HistoryDTO) ToModel() History {
 		for _, branch := range project.Branches {
 			projectBranches[branch.Name] = BranchDetail{
 				Scope:     branch.Scope,
+				Ticket:    branch.Ticket,
+				Draft:     branch.Draft,
+				Types:     branch.Types,
 				UpdatedAt: branch.UpdatedAt,
 			}
 		}
 
 		history.Projects[project.Name] = projectBranches
+
+		if len(project.Descriptions) > 0 {
+			history.Descriptions[project.Name] = project.Descriptions
+		}
 	}
 
 	return history
@@ -0,0 +1,53 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EditorCommand returns the editor used to write commit messages, looked up like git does
+// through $GIT_EDITOR, $VISUAL and $EDITOR.
+func EditorCommand() string {
+	for _, name := range []string{"GIT_EDITOR", "VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return DefaultEditor
+}
+
+// EditorView opens content in the editor and returns it once the editor exits.
+func EditorView(content string) (string, error) {
+	dir, err := os.MkdirTemp("", "kcommit-")
+	if err != nil {
+		return "", fmt.Errorf("EditorView -> %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The same name git uses, so editors enable their commit message mode.
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("EditorView -> %v", err)
+	}
+
+	// The editor may carry arguments, e.g. "code --wait", so it runs through the shell as git does.
+	editor := EditorCommand()
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("EditorView -> %s: %v", editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("EditorView -> %v", err)
+	}
+
+	return string(edited), nil
+}
@@ -0,0 +1,45 @@
+package src
+
+import "testing"
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("GIT_EDITOR", "")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	if editor := EditorCommand(); editor != DefaultEditor {
+		t.Errorf("expected %s without editor variables, got %s", DefaultEditor, editor)
+	}
+
+	t.Setenv("EDITOR", "nano")
+	t.Setenv("VISUAL", "code --wait")
+
+	if editor := EditorCommand(); editor != "code --wait" {
+		t.Errorf("expected VISUAL to take precedence over EDITOR, got %s", editor)
+	}
+
+	t.Setenv("GIT_EDITOR", "vim")
+
+	if editor := EditorCommand(); editor != "vim" {
+		t.Errorf("expected GIT_EDITOR to take precedence, got %s", editor)
+	}
+}
+
+func TestEditorView(t *testing.T) {
+	t.Setenv("GIT_EDITOR", `printf 'fix: edited\n' >>`)
+
+	edited, err := EditorView("feat(cache): add eviction\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if edited != "feat(cache): add eviction\nfix: edited\n" {
+		t.Errorf("unexpected edited content %q", edited)
+	}
+
+	t.Setenv("GIT_EDITOR", "false")
+
+	if _, err := EditorView(""); err == nil {
+		t.Errorf("expected an error when the editor fails")
+	}
+}
@@ -11,14 +11,21 @@ type FileManagerInterface interface {
 	ReadFileContent(filePath string) (string, error)
 	GetHistoryContent() (string, error)
 	WriteHistoryContent(content string) error
+	GetConfigContent() (string, error)
+	ReadCacheContent(name string) (string, error)
+	WriteCacheContent(name, content string) error
+	WriteLastMessage(content string) (string, error)
 	BasicSetup() error
 	GetCurrentDirectoryName() (string, error)
 }
 
 type FileManager struct {
-	HomeDir        string
-	KcommitDir     string
-	KcommitHistory string
+	HomeDir            string
+	KcommitDir         string
+	KcommitHistory     string
+	KcommitConfig      string
+	KcommitLastMessage string
+	KcommitCache       string
 }
 
 func NewFileManager() (*FileManager, error) {
@@ -29,11 +36,17 @@ func NewFileManager() (*FileManager, error) {
 
 	KcommitDir := filepath.Join(homeDir, KcommitDirName)
 	KcommitHistory := filepath.Join(KcommitDir, KcommitHistoryFileName)
+	KcommitConfig := filepath.Join(KcommitDir, KcommitConfigFileName)
+	KcommitLastMessage := filepath.Join(KcommitDir, KcommitLastMessageName)
+	KcommitCache := filepath.Join(KcommitDir, KcommitCacheDirName)
 
 	return &FileManager{
-		HomeDir:        homeDir,
-		KcommitDir:     KcommitDir,
-		KcommitHistory: KcommitHistory,
+		HomeDir:            homeDir,
+		KcommitDir:         KcommitDir,
+		KcommitHistory:     KcommitHistory,
+		KcommitConfig:      KcommitConfig,
+		KcommitLastMessage: KcommitLastMessage,
+		KcommitCache:       KcommitCache,
 	}, nil
 }
 
@@ -96,6 +109,57 @@ func (m *FileManager) WriteHistoryContent(content string) error {
 	return nil
 }
 
+// GetConfigContent returns the user config, it is optional so a missing file is empty.
+func (m *FileManager) GetConfigContent() (string, error) {
+	exists, err := m.CheckIfPathExists(m.KcommitConfig)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	str, err := m.ReadFileContent(m.KcommitConfig)
+	if err != nil {
+		return "", fmt.Errorf("GetConfigContent -> %s %v", m.KcommitConfig, err)
+	}
+	return str, nil
+}
+
+// ReadCacheContent returns a file of ~/.kcommit/cache, a missing file is empty.
+func (m *FileManager) ReadCacheContent(name string) (string, error) {
+	path := filepath.Join(m.KcommitCache, name)
+
+	exists, err := m.CheckIfPathExists(path)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	str, err := m.ReadFileContent(path)
+	if err != nil {
+		return "", fmt.Errorf("ReadCacheContent -> %v", err)
+	}
+	return str, nil
+}
+
+// WriteCacheContent saves a file on ~/.kcommit/cache, creating the directory when needed.
+func (m *FileManager) WriteCacheContent(name, content string) error {
+	if err := os.MkdirAll(m.KcommitCache, 0755); err != nil {
+		return fmt.Errorf("WriteCacheContent -> %v", err)
+	}
+
+	if err := m.writeFileContent(filepath.Join(m.KcommitCache, name), content); err != nil {
+		return fmt.Errorf("WriteCacheContent -> %v", err)
+	}
+	return nil
+}
+
+// WriteLastMessage saves the message of a failed commit and returns where it was written.
+func (m *FileManager) WriteLastMessage(content string) (string, error) {
+	err := m.writeFileContent(m.KcommitLastMessage, content)
+	if err != nil {
+		return "", fmt.Errorf("WriteLastMessage -> %s: %v", m.KcommitLastMessage, err)
+	}
+	return m.KcommitLastMessage, nil
+}
+
 func (m *FileManager) writeFileContent(filePath, content string) error {
 	err := os.WriteFile(filePath, []byte(content), 0644)
 	if err != nil {
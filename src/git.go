@@ -2,7 +2,9 @@ package src
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,16 +13,83 @@ import (
 
 type GitInterface interface {
 	GetCurrentBranch() (string, error)
-	GitCommit(msg string) (string, error)
+	GitCommit(msg string, args ...string) (string, error)
+	GitCommitAmend(msg string, args ...string) (string, error)
+	GetLastCommitMessage() (string, error)
+	GetRecentCommits(limit int) ([]LogEntry, error)
+	GetCommits(revisionRange string) ([]LogEntry, error)
+	GetLatestTag() (string, error)
+	GitAutosquash(target string) (string, error)
+	GitRevertNoCommit(hash string) (string, error)
+	StageAll() error
+	SetOutput(output io.Writer)
 	IsGitRepository() bool
+	GetRepositoryInfo() (RepositoryInfo, error)
+	GetRepositoryState() (RepositoryState, error)
 }
 
-type Git struct{}
+type LogEntry struct {
+	Hash      string
+	ShortHash string
+	Subject   string
+	// Body is the message after the subject, only GetCommits reads it.
+	Body string
+}
+
+// RepositoryInfo describes the repository kcommit runs in, as reported by git.
+type RepositoryInfo struct {
+	TopLevel  string
+	GitDir    string
+	CommonDir string
+	// Worktree is the name of the linked worktree, empty for the main worktree.
+	Worktree string
+}
+
+const (
+	RebaseOperation     = "rebase"
+	MergeOperation      = "merge"
+	CherryPickOperation = "cherry-pick"
+	RevertOperation     = "revert"
+)
+
+// RepositoryState describes an operation in progress that affects how kcommit commits.
+type RepositoryState struct {
+	// Operation is one of the *Operation constants, empty when nothing is in progress.
+	Operation string
+	Detached  bool
+	// Branch is the branch being rebased, HEAD is detached while a rebase runs.
+	Branch string
+	// PreparedMessage is the message git prepared for a merge, cherry-pick or revert.
+	PreparedMessage string
+}
+
+type Git struct {
+	// output receives what git prints on stderr while it runs, e.g. the output of hooks.
+	output io.Writer
+}
 
 func NewGit() *Git {
 	return &Git{}
 }
 
+// NewGitBackend returns the GitInterface implementation for the given backend name.
+// When no backend is chosen the git binary is used if available, go-git otherwise.
+func NewGitBackend(backend string) (GitInterface, error) {
+	switch backend {
+	case GitBackend:
+		return NewGit(), nil
+	case GoGitBackend:
+		return NewGoGit(), nil
+	case "":
+		if _, err := exec.LookPath("git"); err != nil {
+			return NewGoGit(), nil
+		}
+		return NewGit(), nil
+	}
+
+	return nil, fmt.Errorf("NewGitBackend -> unknown backend %s", backend)
+}
+
 func (g *Git) GetCurrentBranch() (string, error) {
 	branch, err := g.execGitCommand("branch", "--show-current")
 	if err == nil && branch != "" {
@@ -34,34 +103,262 @@ func (g *Git) GetCurrentBranch() (string, error) {
 
 	branch, err = g.execGitCommand("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
-		return "", fmt.Errorf("GetCurrentBranch -> %v", err)
+		return "", fmt.Errorf("GetCurrentBranch -> %w", err)
+	}
+
+	if branch == "HEAD" {
+		return "", fmt.Errorf("GetCurrentBranch -> HEAD is detached")
 	}
 
 	return branch, nil
 }
 
-func (g *Git) GitCommit(msg string) (string, error) {
-	output, err := g.execGitCommand("commit", "-m", msg)
+func (g *Git) GitCommit(msg string, args ...string) (string, error) {
+	output, err := g.execGitCommand(append([]string{"commit", "-m", msg}, args...)...)
 	if err != nil {
-		return "", fmt.Errorf("GitCommit -> %v", err)
+		return "", fmt.Errorf("GitCommit -> %w", err)
 	}
 	return output, nil
 }
 
+func (g *Git) GitCommitAmend(msg string, args ...string) (string, error) {
+	output, err := g.execGitCommand(append([]string{"commit", "--amend", "-m", msg}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("GitCommitAmend -> %w", err)
+	}
+	return output, nil
+}
+
+func (g *Git) GetLastCommitMessage() (string, error) {
+	msg, err := g.execGitCommand("log", "-1", "--format=%B")
+	if err != nil {
+		return "", fmt.Errorf("GetLastCommitMessage -> %w", err)
+	}
+	return msg, nil
+}
+
+func (g *Git) GetRecentCommits(limit int) ([]LogEntry, error) {
+	output, err := g.execGitCommand("log", fmt.Sprintf("--max-count=%d", limit), "--format=%H%x1f%h%x1f%s")
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentCommits -> %w", err)
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		entries = append(entries, LogEntry{Hash: fields[0], ShortHash: fields[1], Subject: fields[2]})
+	}
+
+	return entries, nil
+}
+
+// GetCommits lists the commits of a revision range, e.g. v1.0.0..HEAD, newest first.
+func (g *Git) GetCommits(revisionRange string) ([]LogEntry, error) {
+	output, err := g.execGitCommand("log", "--format=%H%x1f%h%x1f%s%x1f%b%x1e", revisionRange, "--")
+	if err != nil {
+		return nil, fmt.Errorf("GetCommits -> %w", err)
+	}
+
+	var entries []LogEntry
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		entries = append(entries, LogEntry{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Subject:   fields[2],
+			Body:      strings.TrimSpace(fields[3]),
+		})
+	}
+
+	return entries, nil
+}
+
+// GetLatestTag returns the most recent tag reachable from HEAD, empty when there is none.
+func (g *Git) GetLatestTag() (string, error) {
+	output, err := g.execGitCommand("tag", "--merged", "HEAD", "--sort=-creatordate")
+	if err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	tag, _, _ := strings.Cut(output, "\n")
+	return tag, nil
+}
+
+// GitAutosquash runs a non interactive rebase that folds fixup!/squash! commits into target.
+func (g *Git) GitAutosquash(target string) (string, error) {
+	base := []string{target + "~1"}
+	if _, err := g.execGitCommand("rev-parse", "--verify", "--quiet", target+"~1"); err != nil {
+		base = []string{"--root"}
+	}
+
+	// Accept the generated todo list and combined squash messages without opening an editor.
+	args := []string{"-c", "sequence.editor=:", "-c", "core.editor=:", "rebase", "--interactive", "--autosquash", "--autostash"}
+	args = append(args, base...)
+
+	output, err := g.execGitCommand(args...)
+	if err != nil {
+		return "", fmt.Errorf("GitAutosquash -> %w", err)
+	}
+	return output, nil
+}
+
+func (g *Git) GitRevertNoCommit(hash string) (string, error) {
+	output, err := g.execGitCommand("revert", "--no-commit", hash)
+	if err != nil {
+		return "", fmt.Errorf("GitRevertNoCommit -> %w", err)
+	}
+	return output, nil
+}
+
+// SetOutput streams the stderr of the next git commands to output, nil stops streaming.
+func (g *Git) SetOutput(output io.Writer) {
+	g.output = output
+}
+
+func (g *Git) StageAll() error {
+	if _, err := g.execGitCommand("add", "--all"); err != nil {
+		return fmt.Errorf("StageAll -> %w", err)
+	}
+	return nil
+}
+
+// IsGitRepository asks git itself, so worktrees, submodules, subdirectories
+// and GIT_DIR/GIT_WORK_TREE are detected as well.
 func (g *Git) IsGitRepository() bool {
-	currentDir, err := os.Getwd()
+	inside, err := g.execGitCommand("rev-parse", "--is-inside-work-tree")
+	return err == nil && inside == "true"
+}
+
+func (g *Git) GetRepositoryInfo() (RepositoryInfo, error) {
+	output, err := g.execGitCommand("rev-parse", "--show-toplevel", "--absolute-git-dir", "--git-common-dir")
 	if err != nil {
-		return false
+		return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> %w", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) != 3 {
+		return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> unexpected rev-parse output %q", output)
+	}
+
+	info := RepositoryInfo{
+		TopLevel:  lines[0],
+		GitDir:    lines[1],
+		CommonDir: lines[2],
+	}
+
+	// --git-common-dir is relative to the current directory when not absolute.
+	if !filepath.IsAbs(info.CommonDir) {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> %w", err)
+		}
+		info.CommonDir = filepath.Join(currentDir, info.CommonDir)
+	}
+
+	info.GitDir = evalSymlinks(info.GitDir)
+	info.CommonDir = evalSymlinks(info.CommonDir)
+
+	if info.GitDir != info.CommonDir {
+		info.Worktree = filepath.Base(info.GitDir)
 	}
 
-	gitDir := filepath.Join(currentDir, ".git")
+	return info, nil
+}
 
-	info, err := os.Stat(gitDir)
+func (g *Git) GetRepositoryState() (RepositoryState, error) {
+	gitDir, err := g.execGitCommand("rev-parse", "--absolute-git-dir")
 	if err != nil {
-		return false
+		return RepositoryState{}, fmt.Errorf("GetRepositoryState -> %w", err)
 	}
 
-	return info.IsDir()
+	_, err = g.execGitCommand("symbolic-ref", "--quiet", "HEAD")
+
+	return readRepositoryState(gitDir, err != nil), nil
+}
+
+// readRepositoryState looks for the files git keeps on gitDir while an operation is in progress.
+func readRepositoryState(gitDir string, detached bool) RepositoryState {
+	state := RepositoryState{Detached: detached}
+
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		headName, err := os.ReadFile(filepath.Join(gitDir, dir, "head-name"))
+		if err != nil {
+			continue
+		}
+
+		state.Operation = RebaseOperation
+
+		branch := strings.TrimSpace(string(headName))
+		if strings.HasPrefix(branch, "refs/heads/") {
+			state.Branch = strings.TrimPrefix(branch, "refs/heads/")
+		}
+
+		return state
+	}
+
+	operations := []struct {
+		head      string
+		operation string
+	}{
+		{"MERGE_HEAD", MergeOperation},
+		{"CHERRY_PICK_HEAD", CherryPickOperation},
+		{"REVERT_HEAD", RevertOperation},
+	}
+
+	for _, o := range operations {
+		if _, err := os.Stat(filepath.Join(gitDir, o.head)); err != nil {
+			continue
+		}
+
+		state.Operation = o.operation
+
+		if msg, err := os.ReadFile(filepath.Join(gitDir, "MERGE_MSG")); err == nil {
+			state.PreparedMessage = stripCommentLines(string(msg))
+		}
+
+		break
+	}
+
+	return state
+}
+
+// stripCommentLines removes the lines git adds as instructions to the prepared messages.
+func stripCommentLines(msg string) string {
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ProjectName is the name used to group branches on the history.
+// Linked worktrees share the name of their main repository.
+func (i RepositoryInfo) ProjectName() string {
+	if i.Worktree != "" && filepath.Base(i.CommonDir) == ".git" {
+		return filepath.Base(filepath.Dir(i.CommonDir))
+	}
+
+	if i.TopLevel == "" {
+		return ""
+	}
+
+	return filepath.Base(i.TopLevel)
+}
+
+func evalSymlinks(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return resolved
 }
 
 func (g *Git) execGitCommand(args ...string) (string, error) {
@@ -70,20 +367,64 @@ func (g *Git) execGitCommand(args ...string) (string, error) {
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	// The messages of git are categorized in English, whatever the locale of the user.
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+
+	// Commits run hooks, whose failures are only told apart from the trace of git.
+	var trace string
+	if len(args) > 0 && args[0] == "commit" {
+		trace = newTraceFile()
+		if trace != "" {
+			defer os.Remove(trace)
+			cmd.Env = append(cmd.Env, "GIT_TRACE2_EVENT="+trace)
+		}
+	}
+
+	// Only stderr is streamed: git sends the output of hooks there, while stdout, e.g. the commit
+	// summary, is returned to be shown once.
+	if g.output != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, g.output)
+	}
 
 	if err := cmd.Run(); err != nil {
-		errOutput := strings.TrimSpace(stderr.String())
-		if errOutput != "" {
-			return "", fmt.Errorf("%s", errOutput)
+		gitErr := &GitError{
+			Command:  args,
+			ExitCode: cmd.ProcessState.ExitCode(),
+			Stdout:   strings.TrimSpace(stdout.String()),
+			Stderr:   strings.TrimSpace(stderr.String()),
+			Err:      err,
 		}
 
-		output := strings.TrimSpace(stdout.String())
-		if output != "" {
-			return "", fmt.Errorf("%s", output)
+		if errors.Is(err, exec.ErrNotFound) {
+			gitErr.Category = GitNotInstalledError
+		} else {
+			gitErr.categorize(readFailedHook(trace))
 		}
 
-		return "", err
+		return "", gitErr
 	}
 
 	return strings.TrimSpace(stdout.String()), nil
 }
+
+// newTraceFile creates an empty file for the trace of a git command, or returns "" when it cannot.
+func newTraceFile() string {
+	file, err := os.CreateTemp("", "kcommit-trace-*.json")
+	if err != nil {
+		return ""
+	}
+	file.Close()
+	return file.Name()
+}
+
+func readFailedHook(trace string) string {
+	if trace == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(trace)
+	if err != nil {
+		return ""
+	}
+	return findFailedHook(content)
+}
@@ -0,0 +1,271 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Every GitInterface backend must pass this suite.
+// Repositories are prepared with the git binary so both backends read the same on-disk state.
+
+func gitBackends() map[string]func() GitInterface {
+	return map[string]func() GitInterface{
+		GitBackend:   func() GitInterface { return NewGit() },
+		GoGitBackend: func() GitInterface { return NewGoGit() },
+	}
+}
+
+func forEachBackend(t *testing.T, test func(t *testing.T, git GitInterface)) {
+	for name, newBackend := range gitBackends() {
+		t.Run(name, func(t *testing.T) {
+			test(t, newBackend())
+		})
+	}
+}
+
+func TestConformanceCurrentBranchOnUnbornRepo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		runGit(t, tempDir, "checkout", "-b", "first-branch")
+		chdir(t, tempDir)
+
+		branch, err := git.GetCurrentBranch()
+		if err != nil {
+			t.Fatalf("expected branch name, got error: %v", err)
+		}
+
+		if branch != "first-branch" {
+			t.Fatalf("expected branch first-branch, got %q", branch)
+		}
+	})
+}
+
+func TestConformanceCommitAndReadHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		chdir(t, tempDir)
+
+		commitFile(t, git, tempDir, "file.txt", "feat(cache): add eviction\n\nEvicts old entries.")
+		commitFile(t, git, tempDir, "file.txt", "fix(cache): handle nil")
+
+		msg, err := git.GetLastCommitMessage()
+		if err != nil {
+			t.Fatalf("failed to read last commit message: %v", err)
+		}
+
+		if msg != "fix(cache): handle nil" {
+			t.Errorf("unexpected last commit message %q", msg)
+		}
+
+		commits, err := git.GetRecentCommits(1)
+		if err != nil {
+			t.Fatalf("failed to list commits: %v", err)
+		}
+
+		if len(commits) != 1 || commits[0].Subject != "fix(cache): handle nil" {
+			t.Fatalf("expected limited recent commits, got %v", commits)
+		}
+
+		commits, err = git.GetRecentCommits(RecentCommitsLimit)
+		if err != nil {
+			t.Fatalf("failed to list commits: %v", err)
+		}
+
+		if len(commits) != 2 || commits[1].Subject != "feat(cache): add eviction" {
+			t.Fatalf("unexpected recent commits %v", commits)
+		}
+
+		if len(commits[1].Hash) != 40 || !strings.HasPrefix(commits[1].Hash, commits[1].ShortHash) {
+			t.Errorf("unexpected hashes %q %q", commits[1].Hash, commits[1].ShortHash)
+		}
+	})
+}
+
+func TestConformanceCommitRange(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		chdir(t, tempDir)
+
+		commitFile(t, git, tempDir, "file.txt", "chore: initial")
+
+		tag, err := git.GetLatestTag()
+		if err != nil || tag != "" {
+			t.Fatalf("expected no tag, got %q, %v", tag, err)
+		}
+
+		runGit(t, tempDir, "tag", "v1.0.0")
+		commitFile(t, git, tempDir, "file.txt", "feat(cache): add eviction\n\nEvicts old entries.\n\nBREAKING CHANGE: the cache is bounded.")
+		commitFile(t, git, tempDir, "file.txt", "fix(cache): handle nil")
+
+		tag, err = git.GetLatestTag()
+		if err != nil || tag != "v1.0.0" {
+			t.Fatalf("expected tag v1.0.0, got %q, %v", tag, err)
+		}
+
+		commits, err := git.GetCommits("v1.0.0..HEAD")
+		if err != nil {
+			t.Fatalf("failed to list commits: %v", err)
+		}
+
+		if len(commits) != 2 || commits[0].Subject != "fix(cache): handle nil" || commits[0].Body != "" {
+			t.Fatalf("unexpected commits %v", commits)
+		}
+
+		if commits[1].Body != "Evicts old entries.\n\nBREAKING CHANGE: the cache is bounded." {
+			t.Errorf("unexpected body %q", commits[1].Body)
+		}
+
+		if commits, err := git.GetCommits("HEAD"); err != nil || len(commits) != 3 {
+			t.Errorf("expected every commit, got %v, %v", commits, err)
+		}
+
+		if _, err := git.GetCommits("v9.9.9..HEAD"); err == nil {
+			t.Errorf("expected an error for an unknown revision")
+		}
+	})
+}
+
+func TestConformanceCommitOptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		chdir(t, tempDir)
+
+		if _, err := git.GitCommit("chore: initial"); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+
+		if _, err := git.GitCommit("chore: nothing"); GitErrorCategoryOf(err) != NothingToCommitError {
+			t.Errorf("expected nothing to commit error, got %v", err)
+		}
+
+		enabled := true
+		args := CommitOptionsDTO{AllowEmpty: &enabled, Signoff: &enabled, Author: "Jane <jane@example.com>"}.Args()
+		if _, err := git.GitCommit("chore: empty", args...); err != nil {
+			t.Fatalf("failed to commit with options: %v", err)
+		}
+
+		msg, err := git.GetLastCommitMessage()
+		if err != nil {
+			t.Fatalf("failed to read last commit message: %v", err)
+		}
+
+		if msg != "chore: empty\n\nSigned-off-by: kcommit <kcommit@example.com>" {
+			t.Errorf("expected signed off message, got %q", msg)
+		}
+
+		if author := gitOutput(t, tempDir, "log", "-1", "--format=%an <%ae>"); author != "Jane <jane@example.com>" {
+			t.Errorf("expected author Jane <jane@example.com>, got %q", author)
+		}
+	})
+}
+
+func TestConformanceAmend(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		chdir(t, tempDir)
+
+		commitFile(t, git, tempDir, "file.txt", "feat: first")
+		commitFile(t, git, tempDir, "file.txt", "fix(login): handel empty password")
+
+		if _, err := git.GitCommitAmend("fix(login): handle empty password"); err != nil {
+			t.Fatalf("failed to amend: %v", err)
+		}
+
+		commits, err := git.GetRecentCommits(RecentCommitsLimit)
+		if err != nil {
+			t.Fatalf("failed to list commits: %v", err)
+		}
+
+		if len(commits) != 2 || commits[0].Subject != "fix(login): handle empty password" {
+			t.Fatalf("expected amended commit to replace HEAD, got %v", commits)
+		}
+	})
+}
+
+func TestConformanceRepositoryDetection(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		repoDir := newTestRepository(t)
+		chdir(t, repoDir)
+		commitFile(t, git, repoDir, "file.txt", "feat: first")
+
+		worktreeDir := filepath.Join(t.TempDir(), "feature-wt")
+		runGit(t, repoDir, "worktree", "add", "-b", "feature", worktreeDir)
+
+		subDir := filepath.Join(worktreeDir, "nested")
+		if err := os.Mkdir(subDir, 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		chdir(t, subDir)
+
+		if !git.IsGitRepository() {
+			t.Fatalf("expected worktree subdirectory to be detected as repository")
+		}
+
+		info, err := git.GetRepositoryInfo()
+		if err != nil {
+			t.Fatalf("failed to read repository info: %v", err)
+		}
+
+		expected := RepositoryInfo{
+			TopLevel:  evalSymlinks(worktreeDir),
+			GitDir:    evalSymlinks(filepath.Join(repoDir, ".git", "worktrees", "feature-wt")),
+			CommonDir: evalSymlinks(filepath.Join(repoDir, ".git")),
+			Worktree:  "feature-wt",
+		}
+
+		if info != expected {
+			t.Errorf("expected %+v, got %+v", expected, info)
+		}
+
+		branch, err := git.GetCurrentBranch()
+		if err != nil || branch != "feature" {
+			t.Errorf("expected branch feature, got %q %v", branch, err)
+		}
+
+		chdir(t, t.TempDir())
+
+		if git.IsGitRepository() {
+			t.Errorf("expected plain directory not to be a repository")
+		}
+
+		if _, err := git.GetRepositoryInfo(); GitErrorCategoryOf(err) != NotARepositoryError {
+			t.Errorf("expected not a repository error, got %v", err)
+		}
+	})
+}
+
+func TestConformanceRepositoryState(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, git GitInterface) {
+		tempDir := newTestRepository(t)
+		chdir(t, tempDir)
+
+		runGit(t, tempDir, "checkout", "-b", "main")
+		commitFile(t, git, tempDir, "file.txt", "feat: base")
+		runGit(t, tempDir, "checkout", "-b", "feature")
+		commitFile(t, git, tempDir, "file.txt", "feat: feature change")
+		runGit(t, tempDir, "checkout", "main")
+		commitFile(t, git, tempDir, "file.txt", "feat: conflicting change")
+
+		state, err := git.GetRepositoryState()
+		if err != nil || state != (RepositoryState{}) {
+			t.Fatalf("expected clean state, got %+v %v", state, err)
+		}
+
+		runGitAllowFailure(t, tempDir, "rebase", "main", "feature")
+
+		state, err = git.GetRepositoryState()
+		if err != nil {
+			t.Fatalf("failed to read state: %v", err)
+		}
+
+		if state.Operation != RebaseOperation || !state.Detached || state.Branch != "feature" {
+			t.Errorf("expected rebase of feature, got %+v", state)
+		}
+
+		if _, err := git.GetCurrentBranch(); err == nil {
+			t.Errorf("expected detached HEAD error from GetCurrentBranch")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type GitErrorCategory string
+
+const (
+	UnknownGitError      GitErrorCategory = "unknown"
+	NothingToCommitError GitErrorCategory = "nothing-to-commit"
+	HookFailedError      GitErrorCategory = "hook-failed"
+	NotARepositoryError  GitErrorCategory = "not-a-repository"
+	GitNotInstalledError GitErrorCategory = "git-not-installed"
+)
+
+// GitError keeps what a failed git command reported so callers can react to the kind of failure.
+type GitError struct {
+	Command  []string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Category GitErrorCategory
+	Err      error
+}
+
+func (e *GitError) Error() string {
+	if e.Stderr != "" {
+		return e.Stderr
+	}
+
+	if e.Stdout != "" {
+		return e.Stdout
+	}
+
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("git %s exited with code %d", strings.Join(e.Command, " "), e.ExitCode)
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// Output is everything the command printed, as the user would see it on the terminal.
+func (e *GitError) Output() string {
+	return strings.TrimSpace(strings.Join([]string{e.Stdout, e.Stderr}, "\n"))
+}
+
+// GitErrorCategoryOf returns the category of the GitError wrapped by err.
+func GitErrorCategoryOf(err error) GitErrorCategory {
+	var gitErr *GitError
+	if errors.As(err, &gitErr) {
+		return gitErr.Category
+	}
+	return UnknownGitError
+}
+
+// categorize guesses the category from the output of git. failedHook is the hook that rejected
+// the command, as reported by the trace of git, since hooks print whatever they want.
+func (e *GitError) categorize(failedHook string) {
+	output := e.Output()
+
+	switch {
+	case failedHook != "":
+		e.Category = HookFailedError
+	case strings.Contains(output, "not a git repository"):
+		e.Category = NotARepositoryError
+	case strings.Contains(output, "nothing to commit"),
+		strings.Contains(output, "no changes added to commit"),
+		strings.Contains(output, "nothing added to commit"):
+		e.Category = NothingToCommitError
+	default:
+		e.Category = UnknownGitError
+	}
+}
+
+// trace2Event is the part of a GIT_TRACE2_EVENT line needed to follow the hooks run by git.
+type trace2Event struct {
+	Event      string `json:"event"`
+	Sid        string `json:"sid"`
+	ChildID    int    `json:"child_id"`
+	ChildClass string `json:"child_class"`
+	HookName   string `json:"hook_name"`
+	Code       int    `json:"code"`
+}
+
+// findFailedHook returns the name of the first hook that exited with an error in the trace2 events.
+func findFailedHook(trace []byte) string {
+	type child struct {
+		sid string
+		id  int
+	}
+	hooks := map[child]string{}
+
+	for _, line := range bytes.Split(trace, []byte("\n")) {
+		var event trace2Event
+		if json.Unmarshal(line, &event) != nil {
+			continue
+		}
+
+		key := child{event.Sid, event.ChildID}
+		switch {
+		case event.Event == "child_start" && event.ChildClass == "hook":
+			hooks[key] = event.HookName
+		case event.Event == "child_exit" && event.Code != 0 && hooks[key] != "":
+			return hooks[key]
+		}
+	}
+
+	return ""
+}
@@ -1,8 +1,12 @@
 package src
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +37,377 @@ func TestGitGetCurrentBranchFromUnbornRepo(t *testing.T) {
 	}
 }
 
+func TestGitCommitAmendRewritesLastMessage(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	git := NewGit()
+
+	if _, err := git.GitCommit("fix(login): handel empty password"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if _, err := git.GitCommitAmend("fix(login): handle empty password"); err != nil {
+		t.Fatalf("failed to amend: %v", err)
+	}
+
+	msg, err := git.GetLastCommitMessage()
+	if err != nil {
+		t.Fatalf("failed to read last commit message: %v", err)
+	}
+
+	if msg != "fix(login): handle empty password" {
+		t.Fatalf("expected amended message, got %q", msg)
+	}
+}
+
+func TestGitAutosquashFoldsFixupCommits(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	git := NewGit()
+
+	commitFile(t, git, tempDir, "first.txt", "feat: first")
+	commitFile(t, git, tempDir, "second.txt", "feat: second")
+	commitFile(t, git, tempDir, "first.txt", "fixup! feat: first")
+
+	commits, err := git.GetRecentCommits(RecentCommitsLimit)
+	if err != nil {
+		t.Fatalf("failed to list commits: %v", err)
+	}
+
+	if len(commits) != 3 || commits[0].Subject != "fixup! feat: first" {
+		t.Fatalf("unexpected commits before autosquash: %v", commits)
+	}
+
+	if _, err := git.GitAutosquash(commits[2].Hash); err != nil {
+		t.Fatalf("failed to autosquash: %v", err)
+	}
+
+	commits, err = git.GetRecentCommits(RecentCommitsLimit)
+	if err != nil {
+		t.Fatalf("failed to list commits: %v", err)
+	}
+
+	if len(commits) != 2 || commits[0].Subject != "feat: second" || commits[1].Subject != "feat: first" {
+		t.Fatalf("unexpected commits after autosquash: %v", commits)
+	}
+}
+
+func TestGitRevertNoCommitStagesTheRevert(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	git := NewGit()
+
+	commitFile(t, git, tempDir, "first.txt", "feat: first")
+	commitFile(t, git, tempDir, "second.txt", "feat: second")
+
+	head := gitOutput(t, tempDir, "rev-parse", "HEAD")
+
+	if _, err := git.GitRevertNoCommit(head); err != nil {
+		t.Fatalf("failed to revert: %v", err)
+	}
+
+	if staged := gitOutput(t, tempDir, "diff", "--cached", "--name-status"); staged != "D\tsecond.txt" {
+		t.Errorf("expected the reverted file staged for deletion, got %q", staged)
+	}
+
+	if after := gitOutput(t, tempDir, "rev-parse", "HEAD"); after != head {
+		t.Errorf("expected no commit to be created, HEAD moved from %s to %s", head, after)
+	}
+}
+
+func TestGitRepositoryDetectionInWorktreeSubdirectory(t *testing.T) {
+	repoDir := newTestRepository(t)
+	git := NewGit()
+
+	chdir(t, repoDir)
+	commitFile(t, git, repoDir, "file.txt", "feat: first")
+
+	worktreeDir := filepath.Join(t.TempDir(), "feature-wt")
+	runGit(t, repoDir, "worktree", "add", "-b", "feature", worktreeDir)
+
+	subDir := filepath.Join(worktreeDir, "nested")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	chdir(t, subDir)
+
+	if !git.IsGitRepository() {
+		t.Fatalf("expected worktree subdirectory to be detected as repository")
+	}
+
+	info, err := git.GetRepositoryInfo()
+	if err != nil {
+		t.Fatalf("failed to read repository info: %v", err)
+	}
+
+	if info.TopLevel != evalSymlinks(worktreeDir) {
+		t.Errorf("expected top level %q, got %q", worktreeDir, info.TopLevel)
+	}
+
+	if info.Worktree != "feature-wt" {
+		t.Errorf("expected worktree feature-wt, got %q", info.Worktree)
+	}
+
+	if info.ProjectName() != filepath.Base(repoDir) {
+		t.Errorf("expected project name %q, got %q", filepath.Base(repoDir), info.ProjectName())
+	}
+
+	chdir(t, t.TempDir())
+
+	if git.IsGitRepository() {
+		t.Errorf("expected plain directory not to be a repository")
+	}
+}
+
+func TestGitRepositoryStateDuringRebaseAndMerge(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	git := NewGit()
+	runGit(t, tempDir, "checkout", "-b", "main")
+	commitFile(t, git, tempDir, "file.txt", "feat: base")
+
+	runGit(t, tempDir, "checkout", "-b", "feature")
+	commitFile(t, git, tempDir, "file.txt", "feat: feature change")
+
+	runGit(t, tempDir, "checkout", "main")
+	commitFile(t, git, tempDir, "file.txt", "feat: conflicting change")
+
+	state, err := git.GetRepositoryState()
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+
+	if state.Operation != "" || state.Detached {
+		t.Fatalf("expected clean state, got %+v", state)
+	}
+
+	// A conflicting rebase stops with HEAD detached.
+	runGitAllowFailure(t, tempDir, "rebase", "main", "feature")
+
+	state, err = git.GetRepositoryState()
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+
+	if state.Operation != RebaseOperation || !state.Detached || state.Branch != "feature" {
+		t.Fatalf("expected rebase of feature, got %+v", state)
+	}
+
+	if _, err := git.GetCurrentBranch(); err == nil {
+		t.Errorf("expected detached HEAD error from GetCurrentBranch")
+	}
+
+	runGit(t, tempDir, "rebase", "--abort")
+	runGit(t, tempDir, "checkout", "main")
+
+	// A conflicting merge keeps the message prepared by git.
+	runGitAllowFailure(t, tempDir, "merge", "feature")
+
+	state, err = git.GetRepositoryState()
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+
+	if state.Operation != MergeOperation || state.PreparedMessage != "Merge branch 'feature'" {
+		t.Fatalf("expected merge with prepared message, got %+v", state)
+	}
+}
+
+func TestGitCommitRejectedByHook(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	hook := filepath.Join(tempDir, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho 'lint failed: main.go'\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	_, err := NewGit().GitCommit("feat: add lint")
+
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("expected GitError, got %v", err)
+	}
+
+	if gitErr.Category != HookFailedError || gitErr.ExitCode != 1 {
+		t.Errorf("expected hook failure with exit code 1, got %s %d", gitErr.Category, gitErr.ExitCode)
+	}
+
+	if gitErr.Output() != "lint failed: main.go" {
+		t.Errorf("expected hook output, got %q", gitErr.Output())
+	}
+
+	var streamed bytes.Buffer
+	git := NewGit()
+	git.SetOutput(&streamed)
+
+	if _, err := git.GitCommit("feat: add lint"); err == nil {
+		t.Fatalf("expected hook to reject the commit")
+	}
+
+	if streamed.String() != "lint failed: main.go\n" {
+		t.Errorf("expected hook output to be streamed, got %q", streamed.String())
+	}
+
+	// The commit summary is returned, it is not streamed as well.
+	streamed.Reset()
+	msg, err := git.GitCommit("feat: add lint", "--no-verify")
+	if err != nil {
+		t.Fatalf("expected --no-verify to skip the hook, got %v", err)
+	}
+
+	if !strings.Contains(msg, "feat: add lint") || streamed.Len() != 0 {
+		t.Errorf("expected the summary to be returned only, got %q and streamed %q", msg, streamed.String())
+	}
+}
+
+func TestGitCommitCategorizesHooksFromTheTrace(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+
+	// Linters print their own error: lines, the hook is still the one that failed.
+	hook := filepath.Join(tempDir, ".git", "hooks", "commit-msg")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho 'error: missing ticket' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	_, err := NewGit().GitCommit("feat: add lint")
+	if category := GitErrorCategoryOf(err); category != HookFailedError {
+		t.Errorf("expected hook failure, got %s: %v", category, err)
+	}
+
+	// A commit that fails without any hook involved is not blamed on one.
+	if err := os.Remove(hook); err != nil {
+		t.Fatalf("failed to remove hook: %v", err)
+	}
+
+	_, err = NewGit().GitCommit("")
+	if category := GitErrorCategoryOf(err); err == nil || category != UnknownGitError {
+		t.Errorf("expected unknown failure for an empty message, got %s: %v", category, err)
+	}
+}
+
+func TestGitErrorCategoriesIgnoreTheLocale(t *testing.T) {
+	tempDir := newTestRepository(t)
+	chdir(t, tempDir)
+	runGit(t, tempDir, "commit", "-m", "feat: first")
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LANGUAGE", "de")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+
+	_, err := NewGit().GitCommit("feat: again")
+	if category := GitErrorCategoryOf(err); category != NothingToCommitError {
+		t.Errorf("expected nothing to commit, got %s: %v", category, err)
+	}
+
+	// Without translations installed the above passes anyway, so check what git is given.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte("#!/bin/sh\necho \"$LC_ALL\"\n"), 0755); err != nil {
+		t.Fatalf("failed to write git: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+
+	if locale, _ := NewGit().execGitCommand("status"); locale != "C" {
+		t.Errorf("expected git to run with LC_ALL=C, got %q", locale)
+	}
+}
+
+func TestFindFailedHook(t *testing.T) {
+	trace := strings.Join([]string{
+		`{"event":"child_start","sid":"a","child_id":0,"child_class":"hook","hook_name":"pre-commit"}`,
+		`{"event":"child_start","sid":"b","child_id":0,"child_class":"?","argv":["git","diff"]}`,
+		`{"event":"child_exit","sid":"b","child_id":0,"code":1}`,
+		`{"event":"child_exit","sid":"a","child_id":0,"code":0}`,
+		`{"event":"child_start","sid":"a","child_id":1,"child_class":"hook","hook_name":"commit-msg"}`,
+		`{"event":"child_exit","sid":"a","child_id":1,"code":1}`,
+	}, "\n")
+
+	if hook := findFailedHook([]byte(trace)); hook != "commit-msg" {
+		t.Errorf("expected commit-msg to fail, got %q", hook)
+	}
+
+	if hook := findFailedHook([]byte(trace[:strings.LastIndex(trace, "\n")])); hook != "" {
+		t.Errorf("expected no failed hook, got %q", hook)
+	}
+}
+
+// newTestRepository creates a repository with one staged file ready to be committed.
+func newTestRepository(t *testing.T) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	runGit(t, tempDir, "init")
+	runGit(t, tempDir, "config", "user.name", "kcommit")
+	runGit(t, tempDir, "config", "user.email", "kcommit@example.com")
+	runGit(t, tempDir, "config", "commit.gpgsign", "false")
+
+	if err := os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, tempDir, "add", "file.txt")
+
+	return tempDir
+}
+
+// commitFile appends the message to name and commits it, so every call produces a change.
+func commitFile(t *testing.T, git GitInterface, dir, name, msg string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	content, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append(content, []byte(msg+"\n")...), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runGit(t, dir, "add", name)
+
+	if _, err := git.GitCommit(msg); err != nil {
+		t.Fatalf("failed to commit %q: %v", msg, err)
+	}
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(originalDir)
+	})
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func runGitAllowFailure(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	_ = cmd.Run()
+}
+
 func runGit(t *testing.T, dir string, args ...string) {
 	t.Helper()
 
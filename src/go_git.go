@@ -0,0 +1,517 @@
+package src
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GoGit implements GitInterface with go-git, so kcommit does not need the git binary.
+// Hooks are not run and operations that need the sequencer (rebase, revert) are not supported.
+type GoGit struct{}
+
+func NewGoGit() *GoGit {
+	return &GoGit{}
+}
+
+var errGoGitUnsupported = errors.New("not supported by the go-git backend")
+
+func (g *GoGit) GetCurrentBranch() (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("GetCurrentBranch -> %w", err)
+	}
+
+	// HEAD is read without resolving it, so unborn branches are reported as well.
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", fmt.Errorf("GetCurrentBranch -> %w", err)
+	}
+
+	if head.Type() != plumbing.SymbolicReference {
+		return "", fmt.Errorf("GetCurrentBranch -> HEAD is detached")
+	}
+
+	return head.Target().Short(), nil
+}
+
+func (g *GoGit) GitCommit(msg string, args ...string) (string, error) {
+	output, err := g.commit(msg, false, args)
+	if err != nil {
+		return "", fmt.Errorf("GitCommit -> %w", err)
+	}
+	return output, nil
+}
+
+func (g *GoGit) GitCommitAmend(msg string, args ...string) (string, error) {
+	output, err := g.commit(msg, true, args)
+	if err != nil {
+		return "", fmt.Errorf("GitCommitAmend -> %w", err)
+	}
+	return output, nil
+}
+
+func (g *GoGit) GetLastCommitMessage() (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("GetLastCommitMessage -> %w", err)
+	}
+
+	commit, err := headCommit(repo)
+	if err != nil {
+		return "", fmt.Errorf("GetLastCommitMessage -> %w", err)
+	}
+
+	return strings.TrimSpace(commit.Message), nil
+}
+
+func (g *GoGit) GetRecentCommits(limit int) ([]LogEntry, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentCommits -> %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentCommits -> %w", err)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentCommits -> %w", err)
+	}
+	defer iter.Close()
+
+	var entries []LogEntry
+	for len(entries) < limit {
+		commit, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GetRecentCommits -> %w", err)
+		}
+
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		entries = append(entries, LogEntry{
+			Hash:      commit.Hash.String(),
+			ShortHash: commit.Hash.String()[:7],
+			Subject:   subject,
+		})
+	}
+
+	return entries, nil
+}
+
+func (g *GoGit) GetCommits(revisionRange string) ([]LogEntry, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("GetCommits -> %w", err)
+	}
+
+	from, to, found := strings.Cut(revisionRange, "..")
+	if !found {
+		from, to = "", revisionRange
+	}
+
+	toHash, err := repo.ResolveRevision(plumbing.Revision(cmp.Or(to, "HEAD")))
+	if err != nil {
+		return nil, fmt.Errorf("GetCommits -> %w", goGitError("log", err))
+	}
+
+	// Commits reachable from the start of the range are not part of it.
+	excluded := map[plumbing.Hash]bool{}
+	if from != "" {
+		fromHash, err := repo.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, fmt.Errorf("GetCommits -> %w", goGitError("log", err))
+		}
+
+		if err := forEachAncestor(repo, *fromHash, func(commit *object.Commit) {
+			excluded[commit.Hash] = true
+		}); err != nil {
+			return nil, fmt.Errorf("GetCommits -> %w", err)
+		}
+	}
+
+	var entries []LogEntry
+	err = forEachAncestor(repo, *toHash, func(commit *object.Commit) {
+		if excluded[commit.Hash] {
+			return
+		}
+
+		subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		entries = append(entries, LogEntry{
+			Hash:      commit.Hash.String(),
+			ShortHash: commit.Hash.String()[:7],
+			Subject:   subject,
+			Body:      strings.TrimSpace(body),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetCommits -> %w", err)
+	}
+
+	return entries, nil
+}
+
+func (g *GoGit) GetLatestTag() (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	reachable := map[plumbing.Hash]bool{}
+	if err := forEachAncestor(repo, head.Hash(), func(commit *object.Commit) {
+		reachable[commit.Hash] = true
+	}); err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	var latest string
+	var latestDate time.Time
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		var commit *object.Commit
+		var date time.Time
+
+		// Annotated tags are dated by the tagger, lightweight tags by their commit.
+		if tag, err := repo.TagObject(ref.Hash()); err == nil {
+			if commit, err = tag.Commit(); err != nil {
+				return nil
+			}
+			date = tag.Tagger.When
+		} else {
+			if commit, err = repo.CommitObject(ref.Hash()); err != nil {
+				return nil
+			}
+			date = commit.Committer.When
+		}
+
+		if reachable[commit.Hash] && (latest == "" || date.After(latestDate)) {
+			latest, latestDate = ref.Name().Short(), date
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("GetLatestTag -> %w", err)
+	}
+
+	return latest, nil
+}
+
+func (g *GoGit) GitAutosquash(target string) (string, error) {
+	return "", fmt.Errorf("GitAutosquash -> %v", errGoGitUnsupported)
+}
+
+func (g *GoGit) GitRevertNoCommit(hash string) (string, error) {
+	return "", fmt.Errorf("GitRevertNoCommit -> %v", errGoGitUnsupported)
+}
+
+// SetOutput is a no-op, go-git does not run hooks so there is nothing to stream.
+func (g *GoGit) SetOutput(output io.Writer) {}
+
+func (g *GoGit) StageAll() error {
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("StageAll -> %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("StageAll -> %w", err)
+	}
+
+	if err := wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return fmt.Errorf("StageAll -> %w", goGitError("add", err))
+	}
+	return nil
+}
+
+func (g *GoGit) IsGitRepository() bool {
+	repo, err := g.open()
+	if err != nil {
+		return false
+	}
+
+	// Bare repositories do not have a worktree to commit from.
+	_, err = repo.Worktree()
+	return err == nil
+}
+
+func (g *GoGit) GetRepositoryInfo() (RepositoryInfo, error) {
+	repo, err := g.open()
+	if err != nil {
+		return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> %w", err)
+	}
+
+	info := RepositoryInfo{TopLevel: evalSymlinks(wt.Filesystem.Root())}
+
+	info.GitDir, err = resolveGitDir(info.TopLevel)
+	if err != nil {
+		return RepositoryInfo{}, fmt.Errorf("GetRepositoryInfo -> %w", err)
+	}
+
+	// Linked worktrees point to the main repository through the commondir file.
+	info.CommonDir = info.GitDir
+	if commonDir, err := os.ReadFile(filepath.Join(info.GitDir, "commondir")); err == nil {
+		path := strings.TrimSpace(string(commonDir))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(info.GitDir, path)
+		}
+		info.CommonDir = evalSymlinks(path)
+	}
+
+	if info.GitDir != info.CommonDir {
+		info.Worktree = filepath.Base(info.GitDir)
+	}
+
+	return info, nil
+}
+
+func (g *GoGit) GetRepositoryState() (RepositoryState, error) {
+	info, err := g.GetRepositoryInfo()
+	if err != nil {
+		return RepositoryState{}, fmt.Errorf("GetRepositoryState -> %w", err)
+	}
+
+	repo, err := g.open()
+	if err != nil {
+		return RepositoryState{}, fmt.Errorf("GetRepositoryState -> %w", err)
+	}
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return RepositoryState{}, fmt.Errorf("GetRepositoryState -> %w", err)
+	}
+
+	return readRepositoryState(info.GitDir, head.Type() != plumbing.SymbolicReference), nil
+}
+
+func (g *GoGit) open() (*git.Repository, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpenWithOptions(currentDir, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, goGitError("rev-parse", err)
+	}
+	return repo, nil
+}
+
+// goGitError reports go-git failures as GitError, with the same categories as the git binary.
+func goGitError(command string, err error) error {
+	gitErr := &GitError{Command: []string{command}, ExitCode: 1, Err: err, Category: UnknownGitError}
+
+	switch {
+	case errors.Is(err, git.ErrRepositoryNotExists):
+		gitErr.Category = NotARepositoryError
+	case errors.Is(err, git.ErrEmptyCommit):
+		gitErr.Category = NothingToCommitError
+	}
+
+	return gitErr
+}
+
+// commit translates the git commit arguments built by CommitOptionsDTO into go-git options.
+func (g *GoGit) commit(msg string, amend bool, args []string) (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	options := &git.CommitOptions{Amend: amend}
+	signoff := false
+	var date *time.Time
+
+	for _, arg := range args {
+		name, value, _ := strings.Cut(arg, "=")
+
+		switch name {
+		case "--all":
+			options.All = true
+		case "--allow-empty":
+			options.AllowEmptyCommits = true
+		case "--no-verify":
+			// go-git never runs hooks.
+		case "--signoff":
+			signoff = true
+		case "--author":
+			options.Author = &object.Signature{}
+			options.Author.Decode([]byte(value))
+			if options.Author.Email == "" {
+				return "", fmt.Errorf("invalid author %q, expected Name <email>", value)
+			}
+		case "--date":
+			parsed, err := parseCommitDate(value)
+			if err != nil {
+				return "", err
+			}
+			date = &parsed
+		default:
+			return "", fmt.Errorf("%s is %v", name, errGoGitUnsupported)
+		}
+	}
+
+	committer, err := configSignature(repo)
+	if err != nil {
+		return "", err
+	}
+
+	// git keeps the original author when amending.
+	if options.Author == nil && amend {
+		commit, err := headCommit(repo)
+		if err != nil {
+			return "", err
+		}
+		author := commit.Author
+		options.Author = &author
+	}
+
+	if options.Author == nil {
+		author := *committer
+		options.Author = &author
+	}
+
+	if date != nil {
+		options.Author.When = *date
+	} else if options.Author.When.IsZero() {
+		options.Author.When = committer.When
+	}
+	options.Committer = committer
+
+	if signoff {
+		trailer := fmt.Sprintf("Signed-off-by: %s <%s>", committer.Name, committer.Email)
+		if !strings.Contains(msg, trailer) {
+			msg = fmt.Sprintf("%s\n\n%s", strings.TrimSpace(msg), trailer)
+		}
+	}
+
+	hash, err := wt.Commit(msg, options)
+	if err != nil {
+		return "", goGitError("commit", err)
+	}
+
+	branch, err := g.GetCurrentBranch()
+	if err != nil {
+		branch = "detached HEAD"
+	}
+
+	subject, _, _ := strings.Cut(msg, "\n")
+	return fmt.Sprintf("[%s %s] %s", branch, hash.String()[:7], subject), nil
+}
+
+func headCommit(repo *git.Repository) (*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(head.Hash())
+}
+
+// forEachAncestor calls fn for from and every commit reachable from it, newest first.
+func forEachAncestor(repo *git.Repository, from plumbing.Hash, fn func(commit *object.Commit)) error {
+	iter, err := repo.Log(&git.LogOptions{From: from, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	return iter.ForEach(func(commit *object.Commit) error {
+		fn(commit)
+		return nil
+	})
+}
+
+// configSignature reads user.name and user.email from the repository, global and system config.
+func configSignature(repo *git.Repository) (*object.Signature, error) {
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.User.Name == "" || cfg.User.Email == "" {
+		return nil, errors.New("user.name and user.email must be configured")
+	}
+
+	return &object.Signature{Name: cfg.User.Name, Email: cfg.User.Email, When: time.Now()}, nil
+}
+
+func parseCommitDate(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+
+	layouts := []string{time.RFC3339, "2006-01-02 15:04:05 -0700", "Mon Jan 2 15:04:05 2006 -0700", "2006-01-02"}
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported date format %q", value)
+}
+
+// resolveGitDir follows the .git file used by linked worktrees and submodules.
+func resolveGitDir(topLevel string) (string, error) {
+	dotGit := filepath.Join(topLevel, ".git")
+
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		return evalSymlinks(dotGit), nil
+	}
+
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+
+	path, found := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !found {
+		return "", fmt.Errorf("invalid .git file %s", dotGit)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(topLevel, path)
+	}
+
+	return evalSymlinks(path), nil
+}
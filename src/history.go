@@ -1,18 +1,25 @@
 package src
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 )
 
 type History struct {
 	Projects map[string]map[string]BranchDetail `json:"projects"`
+	// Descriptions are the last descriptions written on each project, the most recent first.
+	Descriptions map[string][]string `json:"descriptions"`
 }
 
 type BranchDetail struct {
-	Scope     string    `json:"scope"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Scope     string         `json:"scope"`
+	Ticket    string         `json:"ticket"`
+	Draft     *DraftDTO      `json:"draft"`
+	Types     []TypeUsageDTO `json:"types"`
+	UpdatedAt time.Time      `json:"updated_at"`
 }
 
 func (h *History) hasProject(projectName string) bool {
@@ -43,7 +50,96 @@ func (h *History) FindBranchData(projectName string, branchName string) (*Branch
 }
 
 func (h *History) SetBranch(projectName string, branchName string, scope string) {
-	h.Projects[projectName][branchName] = BranchDetail{Scope: scope, UpdatedAt: time.Now()}
+	branch := h.Projects[projectName][branchName]
+	branch.Scope = scope
+	branch.UpdatedAt = time.Now()
+	h.Projects[projectName][branchName] = branch
+}
+
+func (h *History) SetTicket(projectName string, branchName string, ticket string) {
+	branch := h.Projects[projectName][branchName]
+	branch.Ticket = ticket
+	h.Projects[projectName][branchName] = branch
+}
+
+// SetDraft keeps the draft for the branch, nil removes it.
+func (h *History) SetDraft(projectName string, branchName string, draft *DraftDTO) {
+	branch := h.Projects[projectName][branchName]
+	branch.Draft = draft
+	h.Projects[projectName][branchName] = branch
+}
+
+// RecordType counts a use of the commit type on the branch.
+func (h *History) RecordType(projectName string, branchName string, commitType string, usedAt time.Time) {
+	if commitType == "" {
+		return
+	}
+
+	branch := h.Projects[projectName][branchName]
+
+	index := slices.IndexFunc(branch.Types, func(usage TypeUsageDTO) bool { return usage.Type == commitType })
+	if index < 0 {
+		branch.Types = append(branch.Types, TypeUsageDTO{Type: commitType})
+		index = len(branch.Types) - 1
+	}
+
+	branch.Types[index].Count++
+	branch.Types[index].LastUsed = usedAt
+	h.Projects[projectName][branchName] = branch
+}
+
+// RankTypes orders the commit types by their use on the branch, then on the whole project.
+// Types that were never used keep their order after the others.
+func (h *History) RankTypes(projectName string, branchName string, commitTypes []CommitTypeDTO, now time.Time) []CommitTypeDTO {
+	branchScores := map[string]float64{}
+	projectScores := map[string]float64{}
+
+	for name, branch := range h.Projects[projectName] {
+		for _, usage := range branch.Types {
+			score := usage.score(now)
+			projectScores[usage.Type] += score
+			if name == branchName {
+				branchScores[usage.Type] += score
+			}
+		}
+	}
+
+	ranked := slices.Clone(commitTypes)
+	slices.SortStableFunc(ranked, func(a, b CommitTypeDTO) int {
+		if order := cmp.Compare(branchScores[b.Type], branchScores[a.Type]); order != 0 {
+			return order
+		}
+		return cmp.Compare(projectScores[b.Type], projectScores[a.Type])
+	})
+
+	return ranked
+}
+
+// score weighs how many times a type was used by how recently, a use a week ago counts half.
+func (u TypeUsageDTO) score(now time.Time) float64 {
+	weeks := max(now.Sub(u.LastUsed).Hours()/(24*7), 0)
+	return float64(u.Count) / (1 + weeks)
+}
+
+// RecordDescription keeps the description first among the recent ones of the project,
+// only the last RecentDescriptionsLimit are kept.
+func (h *History) RecordDescription(projectName string, description string) {
+	if description == "" {
+		return
+	}
+
+	if h.Descriptions == nil {
+		h.Descriptions = make(map[string][]string)
+	}
+
+	descriptions := slices.DeleteFunc(h.Descriptions[projectName], func(d string) bool { return d == description })
+	descriptions = append([]string{description}, descriptions...)
+	h.Descriptions[projectName] = descriptions[:min(len(descriptions), RecentDescriptionsLimit)]
+}
+
+// RecentDescriptions returns the last descriptions written on the project, the most recent first.
+func (h *History) RecentDescriptions(projectName string) []string {
+	return h.Descriptions[projectName]
 }
 
 func (h *History) addProject(projectName string) {
@@ -82,14 +178,18 @@ func (h *History) ToProjectDTO() []ProjectDTO {
 
 	for projectName, branches := range h.Projects {
 		project := ProjectDTO{
-			Name:     projectName,
-			Branches: []BranchDTO{},
+			Name:         projectName,
+			Branches:     []BranchDTO{},
+			Descriptions: h.Descriptions[projectName],
 		}
 
 		for branchName, branchDetail := range branches {
 			project.Branches = append(project.Branches, BranchDTO{
 				Name:      branchName,
 				Scope:     branchDetail.Scope,
+				Ticket:    branchDetail.Ticket,
+				Draft:     branchDetail.Draft,
+				Types:     branchDetail.Types,
 				UpdatedAt: branchDetail.UpdatedAt,
 			})
 		}
@@ -112,6 +212,7 @@ func (h *History) CleanOldBranches(currentTime time.Time) {
 
 		if len(branches) == 0 {
 			delete(h.Projects, project)
+			delete(h.Descriptions, project)
 		}
 	}
 }
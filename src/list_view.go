@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -50,6 +51,16 @@ func (m ListViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			*m.endValue = ListItem{T: ExitSignal}
 			m.quitting = true
 			return m, tea.Quit
+
+		case "shift+tab", "backspace":
+			// backspace edits the filter while filtering.
+			if m.list.FilterState() == list.Filtering {
+				break
+			}
+
+			*m.endValue = ListItem{T: BackSignal}
+			m.quitting = true
+			return m, tea.Quit
 		}
 	}
 
@@ -95,6 +106,17 @@ func ListView(title string, op []ListItem, height int, endValue *ListItem) {
 	l.Styles.Title.Align(lipgloss.Left)
 	l.Styles.PaginationStyle = styles.PaginationStyle
 	l.Styles.HelpStyle = styles.HelpStyle
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "back"))}
+	}
+
+	// endValue may carry a previous answer, in that case it starts selected.
+	for index, o := range op {
+		if endValue.T != "" && o.T == endValue.T {
+			l.Select(index)
+			break
+		}
+	}
 
 	m := ListViewModel{list: l, endValue: endValue, selected: "", styles: *styles}
 
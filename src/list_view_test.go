@@ -35,3 +35,16 @@ func TestListViewModelClearsViewOnCancel(t *testing.T) {
 		}
 	}
 }
+
+func TestListViewModelGoesBack(t *testing.T) {
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyShiftTab}, {Type: tea.KeyBackspace}} {
+		endValue := ListItem{}
+		model := ListViewModel{endValue: &endValue}
+
+		_, cmd := model.Update(msg)
+
+		if cmd == nil || endValue.T != BackSignal {
+			t.Errorf("expected back signal for key %q, got %q", msg.String(), endValue.T)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package src
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type CommitMessage struct {
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	Ticket      string
+}
+
+// Header renders the first line of the commit message using the template of the rules.
+// {emoji} and {code} are the ones of the type of the message.
+// Placeholders whose value is empty are dropped from the template together with their brackets,
+// the values are written as they are.
+func (m CommitMessage) Header(rules *CommitRulesDTO) string {
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	commitType := rules.findType(m.Type)
+
+	values := map[string]string{
+		"emoji":       commitType.Emoji,
+		"code":        commitType.Code,
+		"type":        m.Type,
+		"scope":       m.Scope,
+		"description": m.Description,
+		"ticket":      m.Ticket,
+	}
+
+	var replacements []string
+	for name, value := range values {
+		placeholder := "{" + name + "}"
+		value = strings.TrimSpace(value)
+
+		if value == "" {
+			template = strings.NewReplacer("("+placeholder+")", "", "["+placeholder+"]", "", placeholder, "").Replace(template)
+			continue
+		}
+		replacements = append(replacements, placeholder, value)
+	}
+
+	template = strings.Join(strings.Fields(template), " ")
+
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// Format builds the full commit message (header, body and trailers) following the rules.
+func (m CommitMessage) Format(rules *CommitRulesDTO) string {
+	parts := []string{m.Header(rules)}
+
+	if body := strings.TrimSpace(m.Body); body != "" {
+		parts = append(parts, body)
+	}
+
+	if trailer := rules.Ticket.TrailerFor(m.Ticket, rules.HeaderTemplate); trailer != "" {
+		parts = append(parts, trailer)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+var templatePlaceholders = []struct {
+	name    string
+	pattern string
+}{
+	// Emoji are not ASCII letters, so a header without one does not give the start of the type away.
+	{"emoji", `[^\s\w()\[\]:!]*`},
+	{"code", `(?::[^\s:]+:)?`},
+	{"type", `[^\s()\[\]:!]+`},
+	{"scope", `[^()]*`},
+	{"ticket", `[^\s()\[\]]*`},
+	{"description", `.+`},
+}
+
+// templateRegexp turns a template into a regular expression with one named group per placeholder.
+// Placeholders wrapped in brackets are optional, mirroring how Header drops them when empty.
+func templateRegexp(template string) (*regexp.Regexp, error) {
+	pattern := regexp.QuoteMeta(template)
+
+	for _, p := range templatePlaceholders {
+		group := fmt.Sprintf("(?P<%s>%s)", p.name, p.pattern)
+		placeholder := regexp.QuoteMeta("{" + p.name + "}")
+
+		pattern = strings.ReplaceAll(pattern, `\(`+placeholder+`\)`, `(?:\(`+group+`\))?`)
+		pattern = strings.ReplaceAll(pattern, `\[`+placeholder+`\]`, `(?:\[`+group+`\])?`)
+		pattern = strings.ReplaceAll(pattern, placeholder, group)
+	}
+
+	pattern = strings.ReplaceAll(pattern, " ", `\s*`)
+
+	re, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return nil, fmt.Errorf("templateRegexp -> %v", err)
+	}
+	return re, nil
+}
+
+func matchTemplate(template, value string) (map[string]string, bool) {
+	re, err := templateRegexp(template)
+	if err != nil {
+		return nil, false
+	}
+
+	match := re.FindStringSubmatch(value)
+	if match == nil {
+		return nil, false
+	}
+
+	values := map[string]string{}
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			values[name] = match[i]
+		}
+	}
+	return values, true
+}
+
+// ParseCommitMessage reads a commit message written with the rules back into its parts.
+// A header that does not follow the template is kept as the description.
+func ParseCommitMessage(raw string, rules *CommitRulesDTO) CommitMessage {
+	raw = strings.TrimSpace(raw)
+
+	header, body, _ := strings.Cut(raw, "\n")
+	body = strings.TrimSpace(body)
+
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	message := CommitMessage{Description: strings.TrimSpace(header)}
+
+	if values, ok := matchTemplate(template, message.Description); ok {
+		message.Type = values["type"]
+		// Templates without {type} tell the type by its emoji or code.
+		if message.Type == "" {
+			message.Type = rules.typeOf(values["emoji"], values["code"])
+		}
+		message.Scope = values["scope"]
+		message.Description = values["description"]
+		message.Ticket = values["ticket"]
+	}
+
+	// The ticket trailer is generated by Format, so it is removed from the body.
+	if rules.Ticket != nil && body != "" {
+		trailer := rules.Ticket.Trailer
+		if trailer == "" {
+			trailer = DefaultTicketTrailer
+		}
+
+		paragraphs := strings.Split(body, "\n\n")
+		last := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+
+		if values, ok := matchTemplate(trailer, last); ok && values["ticket"] != "" {
+			message.Ticket = values["ticket"]
+			body = strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+		}
+	}
+
+	message.Body = body
+	return message
+}
+
+// Validate checks a message written by hand, e.g. in the editor, against the rules.
+func (m CommitMessage) Validate(rules *CommitRulesDTO) error {
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	// Rules without types, e.g. `subsystem: description` headers, leave the type empty.
+	if m.Type == "" && len(rules.CommitTypeDTOs) > 0 {
+		return fmt.Errorf("the header does not follow %s", template)
+	}
+
+	if m.Scope == "" && rules.RequiresScope() {
+		return ErrScopeRequired
+	}
+
+	if err := rules.ValidateDescription(m.Description); err != nil {
+		return err
+	}
+
+	if length, limit := len([]rune(m.Header(rules))), rules.Description.maxHeaderLength(); length > limit {
+		return fmt.Errorf("the header has %d characters, the limit is %d", length, limit)
+	}
+
+	if len(rules.CommitTypeDTOs) > 0 && rules.findType(m.Type).Type == "" {
+		return fmt.Errorf("%s is not an allowed commit type", m.Type)
+	}
+
+	return nil
+}
+
+// ErrScopeRequired is returned by Validate when the template needs a scope and there is none.
+var ErrScopeRequired = errors.New("the scope is required")
+
+// RequiresScope tells whether the header template needs a scope. Only placeholders in brackets are
+// dropped when empty, so `{scope}: {description}` needs one and `{type}({scope}): {description}` does not.
+func (rules *CommitRulesDTO) RequiresScope() bool {
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	template = strings.NewReplacer("({scope})", "", "[{scope}]", "").Replace(template)
+	return strings.Contains(template, "{scope}")
+}
+
+// findType returns the commit type of the rules with the given name, empty when there is none.
+func (rules *CommitRulesDTO) findType(name string) CommitTypeDTO {
+	for _, commitType := range rules.CommitTypeDTOs {
+		if commitType.Type == name {
+			return commitType
+		}
+	}
+	return CommitTypeDTO{}
+}
+
+// typeOf returns the name of the commit type with the given emoji or code, empty when there is none.
+func (rules *CommitRulesDTO) typeOf(emoji, code string) string {
+	for _, commitType := range rules.CommitTypeDTOs {
+		if (emoji != "" && commitType.Emoji == emoji) || (code != "" && commitType.Code == code) {
+			return commitType.Type
+		}
+	}
+	return ""
+}
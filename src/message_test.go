@@ -0,0 +1,139 @@
+package src
+
+import "testing"
+
+func TestParseCommitMessage(t *testing.T) {
+	tests := []struct {
+		rules    CommitRulesDTO
+		raw      string
+		expected CommitMessage
+	}{
+		{
+			CommitRulesDTO{},
+			"feat(cache): add eviction\n",
+			CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"},
+		},
+		{
+			CommitRulesDTO{},
+			"fix: typo in readme\n\nThe link was broken.\n",
+			CommitMessage{Type: "fix", Description: "typo in readme", Body: "The link was broken."},
+		},
+		{
+			CommitRulesDTO{},
+			"Merge branch 'main'",
+			CommitMessage{Description: "Merge branch 'main'"},
+		},
+		{
+			CommitRulesDTO{Ticket: &TicketDTO{}},
+			"feat(cache): add eviction\n\nDetails.\n\nRefs: ABC-123",
+			CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction", Body: "Details.", Ticket: "ABC-123"},
+		},
+		{
+			CommitRulesDTO{HeaderTemplate: "[{ticket}] {type}({scope}): {description}", Ticket: &TicketDTO{}},
+			"[ABC-123] feat(cache): add eviction",
+			CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction", Ticket: "ABC-123"},
+		},
+		{
+			*GitmojiRules(),
+			"✨ feat(cache): add eviction",
+			CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"},
+		},
+		{
+			*GitmojiRules(),
+			"feat(cache): add eviction",
+			CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"},
+		},
+		{
+			*GitmojiRules(),
+			"♻️ refactor: split parse: and format",
+			CommitMessage{Type: "refactor", Description: "split parse: and format"},
+		},
+		{
+			CommitRulesDTO{HeaderTemplate: "{code} ({scope}) {description}", CommitTypeDTOs: GitmojiRules().CommitTypeDTOs},
+			":bug: (cache) handle nil entries",
+			CommitMessage{Type: "fix", Scope: "cache", Description: "handle nil entries"},
+		},
+	}
+
+	for _, test := range tests {
+		got := ParseCommitMessage(test.raw, &test.rules)
+		if got != test.expected {
+			t.Errorf("unexpected parse of %q: expected %+v, got %+v", test.raw, test.expected, got)
+		}
+
+		if test.expected.Type == "" {
+			continue
+		}
+
+		if formatted := got.Format(&test.rules); ParseCommitMessage(formatted, &test.rules) != got {
+			t.Errorf("expected %q to round trip", formatted)
+		}
+	}
+}
+
+func TestCommitMessageValidate(t *testing.T) {
+	rules := &CommitRulesDTO{CommitTypeDTOs: []CommitTypeDTO{{Type: "feat"}, {Type: "fix"}}}
+
+	tests := []struct {
+		message CommitMessage
+		valid   bool
+	}{
+		{CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}, true},
+		{CommitMessage{Type: "fix", Description: "typo"}, true},
+		{CommitMessage{Type: "feature", Description: "add eviction"}, false},
+		{CommitMessage{Type: "feat", Description: " "}, false},
+		{CommitMessage{Description: "add eviction"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.message.Validate(rules); (err == nil) != test.valid {
+			t.Errorf("unexpected validation of %+v: %v", test.message, err)
+		}
+	}
+}
+
+func TestCommitMessageHeaderWithEmoji(t *testing.T) {
+	message := CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction"}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{emoji} {type}({scope}): {description}", "✨ feat(cache): add eviction"},
+		{"{code} {type}({scope}): {description}", ":sparkles: feat(cache): add eviction"},
+		{"{type}({scope}): {description}", "feat(cache): add eviction"},
+	}
+
+	for _, test := range tests {
+		rules := GitmojiRules()
+		rules.HeaderTemplate = test.template
+
+		if got := message.Header(rules); got != test.expected {
+			t.Errorf("expected %q for %s, got %q", test.expected, test.template, got)
+		}
+	}
+
+	// Types without an emoji drop the placeholder.
+	rules := DefaultRules()
+	rules.HeaderTemplate = "{emoji} {type}({scope}): {description}"
+	if got := message.Header(rules); got != "feat(cache): add eviction" {
+		t.Errorf("expected the header without emoji, got %q", got)
+	}
+}
+
+func TestCommitMessageHeaderKeepsValues(t *testing.T) {
+	tests := []struct {
+		message  CommitMessage
+		expected string
+	}{
+		{CommitMessage{Type: "fix", Scope: "api", Description: "call init()  early [] ok"}, "fix(api): call init()  early [] ok"},
+		{CommitMessage{Type: "revert", Scope: "api", Description: "fix(api): handle foo() [] case"}, "revert(api): fix(api): handle foo() [] case"},
+		{CommitMessage{Type: "fix", Description: "handle {scope}"}, "fix: handle {scope}"},
+	}
+
+	for _, test := range tests {
+		if got := test.message.Header(DefaultRules()); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package src
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Answers of the plain prompts that are not values.
+const (
+	plainBack   = "<"
+	plainQuit   = "q"
+	plainEditor = "e"
+	plainClear  = "-"
+
+	plainSuggestionsLimit = 10
+)
+
+// PlainViewBuilder asks line by line, with numbered choices, for when there is no terminal
+// or the interactive screens are not wanted. The answers are read from in and everything else is written to out.
+type PlainViewBuilder struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func NewPlainViewBuilder(in io.Reader, out io.Writer) *PlainViewBuilder {
+	return &PlainViewBuilder{in: bufio.NewReader(in), out: out}
+}
+
+// readLine prints the prompt and reads the answer, false means the input ended.
+func (b *PlainViewBuilder) readLine(prompt string) (string, bool) {
+	fmt.Fprint(b.out, prompt)
+
+	line, err := b.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(b.out)
+		return "", false
+	}
+
+	return strings.TrimSpace(line), true
+}
+
+func (b *PlainViewBuilder) NewListView(title string, op []ListItem, height int) ListItem {
+	return b.list(title, op, "")
+}
+
+func (b *PlainViewBuilder) NewListViewWithSelection(title string, op []ListItem, height int, selected string) ListItem {
+	return b.list(title, op, selected)
+}
+
+// list reads the number or the name of one of the choices. An empty answer keeps selected.
+func (b *PlainViewBuilder) list(title string, op []ListItem, selected string) ListItem {
+	fmt.Fprintf(b.out, "\n%s\n", title)
+	b.printChoices(op)
+
+	prompt := fmt.Sprintf("Choose 1-%d", len(op))
+	if selected != "" {
+		prompt += fmt.Sprintf(" [%s]", selected)
+	}
+	prompt += ", < to go back, q to quit: "
+
+	for {
+		answer, ok := b.readLine(prompt)
+
+		switch {
+		case !ok || answer == plainQuit:
+			return ListItem{T: ExitSignal}
+		case answer == plainBack:
+			return ListItem{T: BackSignal}
+		case answer == "" && selected != "":
+			answer = selected
+		}
+
+		if item, found := findChoice(op, answer); found {
+			return item
+		}
+		fmt.Fprintf(b.out, "%q is not one of the choices\n", answer)
+	}
+}
+
+func (b *PlainViewBuilder) printChoices(op []ListItem) {
+	for index, o := range op {
+		if o.D == "" {
+			fmt.Fprintf(b.out, "  %d) %s\n", index+1, o.T)
+			continue
+		}
+		fmt.Fprintf(b.out, "  %d) %s - %s\n", index+1, o.T, o.D)
+	}
+}
+
+// findChoice finds a choice by its number, starting at 1, or by its name.
+func findChoice(op []ListItem, answer string) (ListItem, bool) {
+	if number, err := strconv.Atoi(answer); err == nil {
+		if number >= 1 && number <= len(op) {
+			return op[number-1], true
+		}
+		return ListItem{}, false
+	}
+
+	for _, o := range op {
+		if o.T == answer {
+			return o, true
+		}
+	}
+	return ListItem{}, false
+}
+
+func (b *PlainViewBuilder) NewTextFieldView(title, placeHolder string, suggestions []string) string {
+	return b.text(title, placeHolder, "", suggestions)
+}
+
+func (b *PlainViewBuilder) NewTextFieldViewWithValue(title, placeHolder, value string, suggestions []string) string {
+	return b.text(title, placeHolder, value, suggestions)
+}
+
+// text reads a value that can not be empty, an empty answer keeps value.
+func (b *PlainViewBuilder) text(title, placeHolder, value string, suggestions []string) string {
+	fmt.Fprintf(b.out, "\n%s\n", title)
+	b.printSuggestions(suggestions)
+
+	for {
+		answer, signal := b.field(placeHolder, value)
+		if signal != "" {
+			return signal
+		}
+		if answer != "" {
+			return answer
+		}
+		fmt.Fprintln(b.out, "A value is required")
+	}
+}
+
+// printSuggestions lists the first suggestions, there is no completion line by line.
+func (b *PlainViewBuilder) printSuggestions(suggestions []string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	fmt.Fprintf(b.out, "Used before: %s\n", strings.Join(suggestions[:min(len(suggestions), plainSuggestionsLimit)], ", "))
+}
+
+// field reads one line, an empty answer keeps value. The signal is set when going back or quitting.
+func (b *PlainViewBuilder) field(placeHolder, value string) (answer string, signal string) {
+	prompt := "> "
+	switch {
+	case value != "":
+		prompt = fmt.Sprintf("[%s] > ", value)
+	case placeHolder != "":
+		prompt = fmt.Sprintf("(%s) > ", placeHolder)
+	}
+
+	answer, ok := b.readLine(prompt)
+	switch {
+	case !ok:
+		return "", ExitSignal
+	case answer == plainBack:
+		return "", BackSignal
+	case answer == "":
+		return value, ""
+	}
+	return answer, ""
+}
+
+func (b *PlainViewBuilder) NewEditorView(content string) (string, error) {
+	return EditorView(content)
+}
+
+// NewComposerView asks the type, scope, description and body one after the other, then shows the message
+// to choose one of the actions. Going back from the type leaves the composer with BackSignal.
+func (b *PlainViewBuilder) NewComposerView(options ComposerOptions) ComposerResult {
+	if options.Rules == nil {
+		options.Rules = DefaultRules()
+	}
+
+	message := options.Message
+	action := ""
+
+	fmt.Fprintf(b.out, "\n%s\n", options.Title)
+
+	// The panes before the focused one keep their answer without asking, going back asks them again.
+	step := func(pane ComposerPane, ask func() stepResult) func() stepResult {
+		return func() stepResult {
+			if action != "" {
+				return skipStep
+			}
+			if pane < options.Focus {
+				return nextStep
+			}
+
+			result := ask()
+			if result == backStep {
+				options.Focus = TypePane
+			}
+			return result
+		}
+	}
+
+	// leave ends the composer, the remaining steps are skipped.
+	leave := func(signal string) stepResult {
+		action = signal
+		return nextStep
+	}
+
+	runSteps(
+		step(TypePane, func() stepResult {
+			// Without types, e.g. `subsystem: description` headers, there is nothing to choose.
+			if len(options.Types) == 0 {
+				return skipStep
+			}
+
+			selected := b.list("Type", options.Types, message.Type)
+			switch selected.T {
+			case ExitSignal, BackSignal:
+				return leave(selected.T)
+			}
+			message.Type = selected.T
+			return nextStep
+		}),
+		step(ScopePane, func() stepResult {
+			required := options.Rules.RequiresScope()
+			if required {
+				fmt.Fprintln(b.out, "\nScope")
+			} else {
+				fmt.Fprintf(b.out, "\nScope, optional, %s to clear it\n", plainClear)
+			}
+			b.printSuggestions(options.ScopeSuggestions)
+
+			for {
+				scope, signal := b.field("scope", message.Scope)
+				switch signal {
+				case ExitSignal:
+					return leave(signal)
+				case BackSignal:
+					if len(options.Types) == 0 {
+						return leave(signal)
+					}
+					return backStep
+				}
+				if scope == plainClear {
+					scope = ""
+				}
+
+				if required && scope == "" {
+					fmt.Fprintln(b.out, ErrScopeRequired)
+					continue
+				}
+
+				message.Scope = scope
+				return nextStep
+			}
+		}),
+		step(DescriptionPane, func() stepResult {
+			fmt.Fprintln(b.out, "\nDescription")
+			for {
+				description, signal := b.field("description", message.Description)
+				switch signal {
+				case ExitSignal:
+					return leave(signal)
+				case BackSignal:
+					return backStep
+				}
+
+				if err := b.checkDescription(message, description, options.Rules); err != nil {
+					fmt.Fprintln(b.out, err)
+					continue
+				}
+
+				message.Description = description
+				header := message.Header(options.Rules)
+				fmt.Fprintf(b.out, "%s  %d/%d\n", header, len([]rune(header)), options.Rules.Description.maxHeaderLength())
+				return nextStep
+			}
+		}),
+		step(BodyPane, func() stepResult {
+			body, signal := b.body(message.Body)
+			switch signal {
+			case ExitSignal:
+				return leave(signal)
+			case BackSignal:
+				return backStep
+			}
+			message.Body = body
+			return nextStep
+		}),
+		step(ConfirmPane, func() stepResult {
+			fmt.Fprintf(b.out, "\n%s\n\n", message.Format(options.Rules))
+			if len(options.Actions) == 0 {
+				return leave("")
+			}
+
+			b.printChoices(options.Actions)
+			prompt := fmt.Sprintf("Choose 1-%d, e to open the editor, < to go back, q to quit: ", len(options.Actions))
+
+			for {
+				answer, ok := b.readLine(prompt)
+				switch {
+				case !ok || answer == plainQuit:
+					return leave(ExitSignal)
+				case answer == plainEditor:
+					return leave(EditorSignal)
+				case answer == plainBack:
+					return backStep
+				}
+
+				if item, found := findChoice(options.Actions, answer); found {
+					return leave(item.T)
+				}
+				fmt.Fprintf(b.out, "%q is not one of the choices\n", answer)
+			}
+		}),
+	)
+
+	return ComposerResult{Message: message, Action: action}
+}
+
+// checkDescription validates the description and the length of the header it makes.
+func (b *PlainViewBuilder) checkDescription(message CommitMessage, description string, rules *CommitRulesDTO) error {
+	if err := rules.ValidateDescription(description); err != nil {
+		return err
+	}
+
+	if limit := rules.DescriptionLimit(message); len([]rune(description)) > limit {
+		return fmt.Errorf("the description can have at most %d characters", limit)
+	}
+
+	return nil
+}
+
+// body reads lines until an empty one. An empty first line keeps value, - clears it.
+func (b *PlainViewBuilder) body(value string) (string, string) {
+	fmt.Fprintf(b.out, "\nBody, optional, end it with an empty line, %s to clear it\n", plainClear)
+	if value != "" {
+		fmt.Fprintf(b.out, "[%s]\n", value)
+	}
+
+	var lines []string
+	for {
+		line, ok := b.readLine("> ")
+		if !ok {
+			if len(lines) == 0 {
+				return "", ExitSignal
+			}
+			break
+		}
+
+		if len(lines) == 0 {
+			switch line {
+			case plainBack:
+				return "", BackSignal
+			case plainClear:
+				return "", ""
+			case "":
+				return value, ""
+			}
+		}
+
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), ""
+}
+
+// NewProgressView prints the title and what the task writes as it goes.
+func (b *PlainViewBuilder) NewProgressView(title string, task func(output io.Writer) (string, error)) (string, error) {
+	fmt.Fprintln(b.out, title)
+	return task(b.out)
+}
@@ -0,0 +1,147 @@
+package src
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func plainBuilder(input string) (*PlainViewBuilder, *bytes.Buffer) {
+	output := &bytes.Buffer{}
+	return NewPlainViewBuilder(strings.NewReader(input), output), output
+}
+
+func TestPlainViewBuilderList(t *testing.T) {
+	choices := []ListItem{{T: "feat", D: "A new feature"}, {T: "fix", D: "A bug fix"}}
+
+	tests := []struct {
+		input    string
+		selected string
+		expected string
+	}{
+		{"2\n", "", "fix"},
+		{"feat\n", "", "feat"},
+		{"\n", "fix", "fix"},
+		{"3\nchore\n\n1\n", "", "feat"},
+		{"<\n", "", BackSignal},
+		{"q\n", "", ExitSignal},
+		{"", "", ExitSignal},
+	}
+
+	for _, test := range tests {
+		builder, output := plainBuilder(test.input)
+
+		if got := builder.NewListViewWithSelection("Select the commit type", choices, 0, test.selected); got.T != test.expected {
+			t.Errorf("expected %s for %q, got %s", test.expected, test.input, got.T)
+		}
+
+		if !strings.Contains(output.String(), "  2) fix - A bug fix") {
+			t.Errorf("expected numbered choices, got %q", output.String())
+		}
+	}
+}
+
+func TestPlainViewBuilderTextField(t *testing.T) {
+	builder, output := plainBuilder("\ncache\n")
+	if got := builder.NewTextFieldView("Scope", "scope", []string{"auth", "cache"}); got != "cache" {
+		t.Errorf("expected the answer after an empty one, got %q", got)
+	}
+	if !strings.Contains(output.String(), "Used before: auth, cache") || !strings.Contains(output.String(), "A value is required") {
+		t.Errorf("expected the empty answer to be refused, got %q", output.String())
+	}
+
+	builder, _ = plainBuilder("\n")
+	if got := builder.NewTextFieldViewWithValue("Scope", "scope", "cache", nil); got != "cache" {
+		t.Errorf("expected an empty answer to keep the value, got %q", got)
+	}
+
+	builder, _ = plainBuilder("<\n")
+	if got := builder.NewTextFieldView("Scope", "scope", nil); got != BackSignal {
+		t.Errorf("expected back, got %q", got)
+	}
+}
+
+func TestPlainViewBuilderComposer(t *testing.T) {
+	input := strings.Join([]string{
+		// Type and scope, then back from the description to change the scope.
+		"feat", "", "<", "-",
+		// An invalid description is asked again.
+		"Added eviction", "add eviction",
+		"Old entries are removed.", "",
+		"2",
+	}, "\n") + "\n"
+
+	options := testComposerOptions()
+	options.Rules = ConventionalRules()
+
+	builder, output := plainBuilder(input)
+	result := builder.NewComposerView(options)
+
+	expected := CommitMessage{Type: "feat", Description: "add eviction", Body: "Old entries are removed."}
+	if result.Message != expected || result.Action != "just print" {
+		t.Errorf("expected %+v with just print, got %+v", expected, result)
+	}
+
+	if !strings.Contains(output.String(), "must start with a lowercase letter") || !strings.Contains(output.String(), "feat: add eviction  18/100") {
+		t.Errorf("expected the validation error and the header preview, got %q", output.String())
+	}
+}
+
+func TestPlainViewBuilderComposerFocus(t *testing.T) {
+	options := testComposerOptions()
+	options.Message.Description = "handle nil entries"
+	options.Focus = ConfirmPane
+
+	// Back from the confirm step asks the body, then the rest is kept.
+	builder, _ := plainBuilder("<\nA body\n\ne\n")
+	result := builder.NewComposerView(options)
+
+	if result.Action != EditorSignal || result.Message.Body != "A body" || result.Message.Description != "handle nil entries" {
+		t.Errorf("expected the editor signal with the body, got %+v", result)
+	}
+
+	builder, _ = plainBuilder("<\n")
+	if result := builder.NewComposerView(testComposerOptions()); result.Action != BackSignal {
+		t.Errorf("expected back from the type, got %+v", result)
+	}
+}
+
+func TestPlainViewBuilderProgress(t *testing.T) {
+	builder, output := plainBuilder("")
+
+	result, err := builder.NewProgressView("Committing", func(w io.Writer) (string, error) {
+		io.WriteString(w, "running hooks\n")
+		return "done", nil
+	})
+
+	if err != nil || result != "done" || output.String() != "Committing\nrunning hooks\n" {
+		t.Errorf("expected the title and the task output, got %q, %q, %v", output.String(), result, err)
+	}
+}
+
+func TestUsePlainPrompts(t *testing.T) {
+	tests := []struct {
+		prompts  string
+		plain    bool
+		terminal bool
+		expected bool
+	}{
+		{"", false, true, false},
+		{"", false, false, true},
+		{AutoPrompts, true, true, true},
+		{PlainPrompts, false, true, true},
+		{InteractivePrompts, false, false, false},
+	}
+
+	for _, test := range tests {
+		config := &UserConfigDTO{Prompts: test.prompts}
+		if got, err := config.UsePlainPrompts(test.plain, test.terminal); err != nil || got != test.expected {
+			t.Errorf("expected %v for %+v, got %v, %v", test.expected, test, got, err)
+		}
+	}
+
+	if _, err := (&UserConfigDTO{Prompts: "fancy"}).UsePlainPrompts(false, true); err == nil {
+		t.Errorf("expected an error for unknown prompts")
+	}
+}
@@ -0,0 +1,107 @@
+package src
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Names of the built-in rules that .kcommitrc can choose with "preset".
+const (
+	KarmaPreset        = "karma"
+	ConventionalPreset = "conventional"
+	AngularPreset      = "angular"
+	GitmojiPreset      = "gitmoji"
+	LinuxKernelPreset  = "linux-kernel"
+)
+
+// Presets are the built-in rules by name.
+var Presets = map[string]func() *CommitRulesDTO{
+	KarmaPreset:        DefaultRules,
+	ConventionalPreset: ConventionalRules,
+	AngularPreset:      AngularRules,
+	GitmojiPreset:      GitmojiRules,
+	LinuxKernelPreset:  LinuxKernelRules,
+}
+
+// PresetRules returns the rules of the preset with the given name.
+func PresetRules(name string) (*CommitRulesDTO, error) {
+	preset, ok := Presets[name]
+	if !ok {
+		names := slices.Sorted(maps.Keys(Presets))
+		return nil, fmt.Errorf("PresetRules -> unknown preset %s, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	rules := preset()
+	rules.Preset = name
+	return rules, nil
+}
+
+// ResolveRules builds the rules of a .kcommitrc. Without a preset its types replace the default ones,
+// with a preset they are added to the ones of the preset and the other settings override the preset's.
+// shared are the rules it extends, if any, they are resolved the same way and custom is added on top.
+// The preset of custom, when set, is the one the shared rules start from.
+func ResolveRules(custom *CommitRulesDTO, shared *CommitRulesDTO) (*CommitRulesDTO, error) {
+	if shared != nil {
+		base := *shared
+		if custom.Preset != "" {
+			base.Preset = custom.Preset
+		}
+
+		rules, err := ResolveRules(&base, nil)
+		if err != nil {
+			return nil, fmt.Errorf("ResolveRules -> %v", err)
+		}
+		return rules.Extend(custom), nil
+	}
+
+	if custom.Preset == "" {
+		rules := *custom
+		// Keep the default commit types when the project only customizes other settings.
+		if len(rules.CommitTypeDTOs) == 0 {
+			rules.CommitTypeDTOs = DefaultRules().CommitTypeDTOs
+		}
+		return &rules, nil
+	}
+
+	preset, err := PresetRules(custom.Preset)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveRules -> %v", err)
+	}
+
+	return preset.Extend(custom), nil
+}
+
+// Extend returns the rules with the settings of other on top: types with the same name are replaced
+// and new ones are added, the rest of the settings override these when they are set.
+func (rules *CommitRulesDTO) Extend(other *CommitRulesDTO) *CommitRulesDTO {
+	extended := *rules
+
+	extended.CommitTypeDTOs = slices.Clone(rules.CommitTypeDTOs)
+	for _, commitType := range other.CommitTypeDTOs {
+		index := slices.IndexFunc(extended.CommitTypeDTOs, func(t CommitTypeDTO) bool { return t.Type == commitType.Type })
+		if index < 0 {
+			extended.CommitTypeDTOs = append(extended.CommitTypeDTOs, commitType)
+			continue
+		}
+		extended.CommitTypeDTOs[index] = commitType
+	}
+
+	if other.HeaderTemplate != "" {
+		extended.HeaderTemplate = other.HeaderTemplate
+	}
+	if other.Ticket != nil {
+		extended.Ticket = other.Ticket
+	}
+	if other.CommitOptions != nil {
+		extended.CommitOptions = other.CommitOptions
+	}
+
+	extended.Description = rules.Description.extend(other.Description)
+	extended.Scopes = appendUnique(slices.Clone(rules.Scopes), other.Scopes...)
+	extended.PinTypeOrder = rules.PinTypeOrder || other.PinTypeOrder
+	extended.Changelog = rules.Changelog.extend(other.Changelog)
+
+	return &extended
+}
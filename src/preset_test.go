@@ -0,0 +1,81 @@
+package src
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveRulesWithoutPreset(t *testing.T) {
+	rules, err := ResolveRules(&CommitRulesDTO{CommitTypeDTOs: []CommitTypeDTO{{Type: "story"}}}, nil)
+	if err != nil || len(rules.CommitTypeDTOs) != 1 || rules.CommitTypeDTOs[0].Type != "story" {
+		t.Errorf("expected the types of .kcommitrc to replace the default ones, got %+v, %v", rules, err)
+	}
+
+	rules, err = ResolveRules(&CommitRulesDTO{HeaderTemplate: "{type}: {description}"}, nil)
+	if err != nil || len(rules.CommitTypeDTOs) != len(DefaultRules().CommitTypeDTOs) {
+		t.Errorf("expected the default types to be kept, got %+v, %v", rules, err)
+	}
+}
+
+func TestResolveRulesExtendsPreset(t *testing.T) {
+	maxHeaderLength := 72
+	custom := &CommitRulesDTO{
+		Preset: AngularPreset,
+		CommitTypeDTOs: []CommitTypeDTO{
+			{Type: "feat", Description: "A new feature for users."},
+			{Type: "chore", Description: "Maintenance."},
+		},
+		Description: &DescriptionRulesDTO{MaxHeaderLength: maxHeaderLength},
+	}
+
+	rules, err := ResolveRules(custom, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	angular := AngularRules()
+	if len(rules.CommitTypeDTOs) != len(angular.CommitTypeDTOs)+1 || rules.CommitTypeDTOs[len(rules.CommitTypeDTOs)-1].Type != "chore" {
+		t.Errorf("expected chore to be added to the angular types, got %+v", rules.CommitTypeDTOs)
+	}
+
+	if commitType := rules.findType("feat"); commitType.Description != "A new feature for users." {
+		t.Errorf("expected feat to be replaced, got %+v", commitType)
+	}
+
+	if rules.HeaderTemplate != DefaultHeaderTemplate || rules.Description.maxHeaderLength() != maxHeaderLength {
+		t.Errorf("expected the preset template with the custom header length, got %+v", rules)
+	}
+
+	if len(angular.CommitTypeDTOs) != len(AngularRules().CommitTypeDTOs) {
+		t.Errorf("expected the preset to be left as is")
+	}
+
+	if _, err := ResolveRules(&CommitRulesDTO{Preset: "karmic"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown preset")
+	}
+}
+
+func TestLinuxKernelPreset(t *testing.T) {
+	rules, err := PresetRules(LinuxKernelPreset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	message := ParseCommitMessage("mm/slab: Fix a leak on the error path", rules)
+	if message.Scope != "mm/slab" || message.Description != "Fix a leak on the error path" {
+		t.Errorf("expected the subsystem as the scope, got %+v", message)
+	}
+
+	if err := message.Validate(rules); err != nil {
+		t.Errorf("expected the message to be valid without a type, got %v", err)
+	}
+
+	message.Scope = ""
+	if err := message.Validate(rules); !errors.Is(err, ErrScopeRequired) {
+		t.Errorf("expected the subsystem to be required, got %v", err)
+	}
+
+	if DefaultRules().RequiresScope() {
+		t.Errorf("expected the scope of the default template to be optional")
+	}
+}
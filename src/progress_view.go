@@ -0,0 +1,128 @@
+package src
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type progressDoneMsg struct {
+	output string
+	err    error
+}
+
+type progressViewModel struct {
+	spinner  spinner.Model
+	title    string
+	result   *progressDoneMsg
+	quitting bool
+	styles   *Styles
+}
+
+func ProgressViewModel(title string, result *progressDoneMsg) progressViewModel {
+	styles := DefaultStyles()
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.SelectedTitleColor)
+
+	return progressViewModel{
+		spinner: s,
+		title:   title,
+		result:  result,
+		styles:  styles,
+	}
+}
+
+func (m progressViewModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m progressViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressDoneMsg:
+		*m.result = msg
+		m.quitting = true
+		return m, tea.Quit
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	// Keys are ignored, the running command can not be cancelled safely.
+	return m, nil
+}
+
+func (m progressViewModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	return fmt.Sprintf("\n %s %s\n", m.spinner.View(), m.styles.TitleStyle.Render(m.title))
+}
+
+// progressWriter prints every complete line written to it above the progress view.
+// Lines are queued on the program in order, so they are all printed before the view quits.
+type progressWriter struct {
+	println func(args ...interface{})
+	mu      sync.Mutex
+	buffer  bytes.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer.Write(p)
+
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line for the next write.
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			break
+		}
+		w.println(line[:len(line)-1])
+	}
+
+	return len(p), nil
+}
+
+func (w *progressWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buffer.Len() > 0 {
+		w.println(w.buffer.String())
+		w.buffer.Reset()
+	}
+}
+
+// ProgressView shows a spinner while task runs and prints what task writes to output live.
+func ProgressView(title string, task func(output io.Writer) (string, error)) (string, error) {
+	result := progressDoneMsg{}
+	p := tea.NewProgram(ProgressViewModel(title, &result))
+	writer := &progressWriter{println: p.Println}
+
+	go func() {
+		output, err := task(writer)
+		writer.flush()
+		p.Send(progressDoneMsg{output: output, err: err})
+	}()
+
+	if _, err := p.Run(); err != nil {
+		fmt.Println("ProgressView -> ", err)
+		os.Exit(1)
+	}
+
+	return result.output, result.err
+}
@@ -0,0 +1,44 @@
+package src
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestProgressViewModelStoresResultWhenDone(t *testing.T) {
+	result := progressDoneMsg{}
+	model := ProgressViewModel("Running git commit...", &result)
+
+	hookErr := errors.New("hook failed")
+	updatedModel, cmd := model.Update(progressDoneMsg{output: "", err: hookErr})
+
+	if cmd == nil {
+		t.Errorf("expected quit command when task is done")
+	}
+
+	if result.err != hookErr {
+		t.Errorf("expected task error to be kept, got %v", result.err)
+	}
+
+	if view := updatedModel.View(); view != "" {
+		t.Errorf("expected empty view after task is done, got %q", view)
+	}
+}
+
+func TestProgressWriterPrintsLinesInOrder(t *testing.T) {
+	var lines []string
+	writer := &progressWriter{println: func(args ...interface{}) {
+		lines = append(lines, fmt.Sprint(args...))
+	}}
+
+	fmt.Fprint(writer, "running linters\nlint ")
+	fmt.Fprint(writer, "ok\nlast line")
+	writer.flush()
+
+	expected := []string{"running linters", "lint ok", "last line"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected lines %v, got %v", expected, lines)
+	}
+}
@@ -1,5 +1,14 @@
 package src
 
+// Label is the description of the type, after its emoji when it has one.
+func (t CommitTypeDTO) Label() string {
+	if t.Emoji == "" {
+		return t.Description
+	}
+	return t.Emoji + " " + t.Description
+}
+
+// DefaultRules are the karma types, used when .kcommitrc does not choose a preset.
 func DefaultRules() *CommitRulesDTO {
 	l := []CommitTypeDTO{
 		{
@@ -52,3 +61,94 @@ func DefaultRules() *CommitRulesDTO {
 		CommitTypeDTOs: l,
 	}
 }
+
+// GitmojiRules are the default types with their gitmoji, the emoji goes before the header.
+func GitmojiRules() *CommitRulesDTO {
+	l := []CommitTypeDTO{
+		{Type: "feat", Description: "Introduces a new feature.", Emoji: "✨", Code: ":sparkles:"},
+		{Type: "fix", Description: "Fixes a bug.", Emoji: "🐛", Code: ":bug:"},
+		{Type: "hotfix", Description: "Critical hotfix.", Emoji: "🚑️", Code: ":ambulance:"},
+		{Type: "chore", Description: "Adds or updates configuration files.", Emoji: "🔧", Code: ":wrench:"},
+		{Type: "style", Description: "Improves structure or format of the code.", Emoji: "🎨", Code: ":art:"},
+		{Type: "refactor", Description: "Refactors code.", Emoji: "♻️", Code: ":recycle:"},
+		{Type: "test", Description: "Adds, updates or passes tests.", Emoji: "✅", Code: ":white_check_mark:"},
+		{Type: "build", Description: "Adds or updates build scripts or dependencies.", Emoji: "📦️", Code: ":package:"},
+		{Type: "revert", Description: "Reverts changes.", Emoji: "⏪️", Code: ":rewind:"},
+		{Type: "perf", Description: "Improves performance.", Emoji: "⚡️", Code: ":zap:"},
+		{Type: "ci", Description: "Adds or updates the CI build system.", Emoji: "👷", Code: ":construction_worker:"},
+		{Type: "docs", Description: "Adds or updates documentation.", Emoji: "📝", Code: ":memo:"},
+		{Type: "security", Description: "Fixes security or privacy issues.", Emoji: "🔒️", Code: ":lock:"},
+		{Type: "remove", Description: "Removes code or files.", Emoji: "🔥", Code: ":fire:"},
+	}
+
+	return &CommitRulesDTO{
+		CommitTypeDTOs: l,
+		HeaderTemplate: "{emoji} {type}({scope}): {description}",
+	}
+}
+
+// ConventionalRules follow Conventional Commits, with the types of commitlint's config-conventional.
+func ConventionalRules() *CommitRulesDTO {
+	l := []CommitTypeDTO{
+		{Type: "feat", Description: "A new feature."},
+		{Type: "fix", Description: "A bug fix."},
+		{Type: "docs", Description: "Documentation only changes."},
+		{Type: "style", Description: "Changes that do not affect the meaning of the code (white-space, formatting, etc)."},
+		{Type: "refactor", Description: "A code change that neither fixes a bug nor adds a feature."},
+		{Type: "perf", Description: "A code change that improves performance."},
+		{Type: "test", Description: "Adding missing tests or correcting existing tests."},
+		{Type: "build", Description: "Changes that affect the build system or external dependencies."},
+		{Type: "ci", Description: "Changes to the CI configuration files and scripts."},
+		{Type: "chore", Description: "Other changes that do not modify src or test files."},
+		{Type: "revert", Description: "Reverts a previous commit."},
+	}
+
+	return &CommitRulesDTO{
+		CommitTypeDTOs: l,
+		HeaderTemplate: DefaultHeaderTemplate,
+		Description:    strictDescriptionRules(100),
+	}
+}
+
+// AngularRules follow the commit message guidelines of Angular.
+func AngularRules() *CommitRulesDTO {
+	l := []CommitTypeDTO{
+		{Type: "build", Description: "Changes that affect the build system or external dependencies."},
+		{Type: "ci", Description: "Changes to the CI configuration files and scripts."},
+		{Type: "docs", Description: "Documentation only changes."},
+		{Type: "feat", Description: "A new feature."},
+		{Type: "fix", Description: "A bug fix."},
+		{Type: "perf", Description: "A code change that improves performance."},
+		{Type: "refactor", Description: "A code change that neither fixes a bug nor adds a feature."},
+		{Type: "test", Description: "Adding missing tests or correcting existing tests."},
+	}
+
+	return &CommitRulesDTO{
+		CommitTypeDTOs: l,
+		HeaderTemplate: DefaultHeaderTemplate,
+		Description:    strictDescriptionRules(100),
+	}
+}
+
+// strictDescriptionRules turn on every description check, as commitlint does for conventional commits.
+func strictDescriptionRules(maxHeaderLength int) *DescriptionRulesDTO {
+	on := true
+
+	return &DescriptionRulesDTO{
+		MaxHeaderLength:  maxHeaderLength,
+		LowercaseFirst:   &on,
+		NoTrailingPeriod: &on,
+		ImperativeMood:   &on,
+	}
+}
+
+// LinuxKernelRules write `subsystem: description` headers, the subsystem is the scope and there are no types.
+func LinuxKernelRules() *CommitRulesDTO {
+	return &CommitRulesDTO{
+		CommitTypeDTOs: []CommitTypeDTO{},
+		HeaderTemplate: "{scope}: {description}",
+		Description: &DescriptionRulesDTO{
+			MaxHeaderLength: 75,
+		},
+	}
+}
@@ -1,15 +1,34 @@
 package src
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
 type Runner struct {
-	fileManager FileManagerInterface
-	git         GitInterface
-	utils       UtilsInterface
-	viewBuilder ViewBuilderInterface
+	fileManager   FileManagerInterface
+	git           GitInterface
+	utils         UtilsInterface
+	viewBuilder   ViewBuilderInterface
+	commitOptions CommitOptionsDTO
+	repository    RepositoryInfo
+	// output receives what kcommit prints to be piped, e.g. the changelog.
+	output io.Writer
+
+	// The draft of the message being written, saved on history when kcommit is
+	// cancelled or the commit fails. history is nil when the branch is not tracked.
+	draft        DraftDTO
+	history      *History
+	draftProject string
+	draftBranch  string
 }
 
 func NewRunner(fm FileManagerInterface, g GitInterface, u UtilsInterface, b ViewBuilderInterface) *Runner {
@@ -18,18 +37,27 @@ func NewRunner(fm FileManagerInterface, g GitInterface, u UtilsInterface, b View
 		git:         g,
 		utils:       u,
 		viewBuilder: b,
+		output:      os.Stdout,
 	}
 }
 
+// SetOutput sets where the changelog is printed, stdout by default.
+func (r *Runner) SetOutput(output io.Writer) {
+	r.output = output
+}
+
+// SetCommitOptions sets the git commit options given on the command line.
+// They take precedence over the commitOptions defined on .kcommitrc.
+func (r *Runner) SetCommitOptions(options CommitOptionsDTO) {
+	r.commitOptions = options
+}
+
 func (r *Runner) Start() {
-	rules := DefaultRules()
 	styles := DefaultStyles()
 
-	// Check if current dir has .git (is local repository)
+	// Check if current dir is inside a git repository.
 	// This is the return early error.
-	if !r.git.IsGitRepository() {
-		r.utils.ExitWithError("Current directory is not a git repository")
-	}
+	r.checkRepository()
 
 	// Init and setup
 	// Create instance of FileManager and setup.
@@ -40,44 +68,50 @@ func (r *Runner) Start() {
 
 	r.fileManager.BasicSetup()
 
-	// Check for rules on current dir
-	// It may find .kcommitrc or not (not mandatory)
-	// In case current project does not have .kcommitrc it should use a default config (DefaultRules)
-	// More about kcommitrc on README.md.
+	rules := r.loadRules()
 
-	hasCustomConfig, err := r.fileManager.CheckIfPathExists(KcommitRcFileName)
-	if err != nil {
-		r.utils.HandleError(err, "Failed load kcommitrc")
-	}
-
-	if hasCustomConfig {
-
-		customConfigStr, err := r.fileManager.ReadFileContent(KcommitRcFileName)
-		if err != nil {
-			r.utils.HandleError(err, "Failed to read .kcommitrc. Check if the formmat ir correct")
-		}
+	// It should fetch some basic info in order to continue.
+	// - Get repository name as project name (linked worktrees share it)
+	// - Get current branch
+	// Those information should be needed to set kcommit_history.
 
-		customRules, err := ParseJSONContent[CommitRulesDTO](customConfigStr)
+	currentProjName := r.repository.ProjectName()
+	if currentProjName == "" {
+		dirName, err := r.fileManager.GetCurrentDirectoryName()
 		if err != nil {
-			r.utils.HandleError(err, "Failed to parse .kcommitrc")
+			r.utils.HandleError(err, "Failed to read current dir name")
 		}
-
-		rules = customRules
+		currentProjName = dirName
 	}
 
-	// It should fetch some basic info in order to continue.
-	// - Get current dir name as project name
-	// - Get current branch
-	// Those information should be needed to set kcommit_history.
+	// Operations in progress change where the branch comes from:
+	// - rebase: HEAD is detached, the scope of the branch being rebased is used.
+	// - merge, cherry-pick or revert: git prepared a message that may be kept as is.
+	// - detached HEAD: there is no branch to save the scope for.
 
-	currentProjName, err := r.fileManager.GetCurrentDirectoryName()
+	state, err := r.git.GetRepositoryState()
 	if err != nil {
-		r.utils.HandleError(err, "Failed to read current dir name")
+		r.utils.HandleError(err, "Failed to read git repository state")
 	}
 
-	currentBranchName, err := r.git.GetCurrentBranch()
-	if err != nil {
-		r.utils.HandleError(err, "Failed to get current branch")
+	if state.PreparedMessage != "" {
+		if r.commitPreparedMessage(state, rules) {
+			return
+		}
+	}
+
+	trackBranch := !state.Detached || state.Branch != ""
+	currentBranchName := state.Branch
+
+	if state.Operation == RebaseOperation && state.Branch != "" {
+		println(styles.Text(fmt.Sprintf("Rebase in progress, using the scope of %s", state.Branch), styles.PeachColor))
+	} else if !trackBranch {
+		println(styles.Text("HEAD is detached, the scope will not be saved", styles.PeachColor))
+	} else if currentBranchName == "" {
+		currentBranchName, err = r.git.GetCurrentBranch()
+		if err != nil {
+			r.utils.HandleError(err, "Failed to get current branch")
+		}
 	}
 
 	// Get the history content. If it's empty just set a basic structure.
@@ -117,91 +151,209 @@ func (r *Runner) Start() {
 
 	// Check if history has project/branch.
 	// Add project/branch to current history if needed.
-	if !history.HasBranch(currentProjName, currentBranchName) {
-		history.AddBranch(currentProjName, currentBranchName)
-	}
+	// A detached HEAD is not tracked, its scope is asked for every commit.
+	branchData := &BranchDetail{}
 
-	branchData, err := history.FindBranchData(currentProjName, currentBranchName)
-	if err != nil {
-		r.utils.HandleError(err, "Failed to locate project data")
+	if trackBranch {
+		if !history.HasBranch(currentProjName, currentBranchName) {
+			history.AddBranch(currentProjName, currentBranchName)
+		}
+
+		branchData, err = history.FindBranchData(currentProjName, currentBranchName)
+		if err != nil {
+			r.utils.HandleError(err, "Failed to locate project data")
+		}
 	}
 
-	// Define scope for current branch in case it's empty
-	if branchData.Scope == "" {
-		choices := []ListItem{
-			{
-				T: "branch",
-				D: "use branch name as scope",
-			},
-			{
-				T: "custom",
-				D: "write a custom string to be the scope",
-			},
+	// Extract the issue key from the branch name when the project asks for it.
+	// The key is stored next to the scope so it survives branch renames and manual edits.
+	if rules.Ticket != nil && branchData.Ticket == "" && trackBranch {
+		ticket, err := rules.Ticket.ExtractTicket(currentBranchName)
+		if err != nil {
+			r.utils.HandleError(err, "Failed to extract ticket from branch name")
 		}
+		branchData.Ticket = ticket
+		history.SetTicket(currentProjName, currentBranchName, ticket)
+	}
 
-		answer := r.viewBuilder.NewListView("This branch does not have scope defined yet.", choices, 16)
-		r.utils.ValidateInput(answer.T)
+	// The types most used on the branch, then on the project, are listed first.
+	if !rules.PinTypeOrder {
+		rules.CommitTypeDTOs = history.RankTypes(currentProjName, currentBranchName, rules.CommitTypeDTOs, time.Now())
+	}
 
-		if answer.T == "branch" {
-			branchData.Scope = currentBranchName
-		} else {
-			newValue := r.viewBuilder.NewTextFieldView("Write a name for the scope", "")
-			r.utils.ValidateInput(newValue)
-			branchData.Scope = newValue
-		}
+	// From here on the answers are kept as a draft.
+	// A draft left by a cancelled or failed commit can be resumed.
+
+	r.draft = DraftDTO{Scope: branchData.Scope}
 
+	if trackBranch {
+		r.history = &history
+		r.draftProject = currentProjName
+		r.draftBranch = currentBranchName
 	}
 
-	// This will set the scope to be saved and the time it was updated.
-	// Time updated is also used later to clear out old branches
-	history.SetBranch(currentProjName, currentBranchName, branchData.Scope)
+	recent := r.recentMessages(rules)
+	descriptions := descriptionSuggestions(&history, currentProjName, recent)
+	scopes := scopeSuggestions(&history, currentProjName, rules, recent)
 
-	// Choose commit type
+	// The prompts run as steps, going back shows the previous one with its answer selected.
 
-	commitTypeOptions := r.utils.CommitTypeDTOsToListItems(rules.CommitTypeDTOs)
-	selectCommitType := r.viewBuilder.NewListView("Please choose a commit type", commitTypeOptions, 32)
-	r.utils.ValidateInput(selectCommitType.T)
+	askScope := branchData.Scope == ""
+	scopeChoice := ""
+	draftChoice := ""
 
-	// Write commit message
+	var message CommitMessage
+	var action string
 
-	commitDescription := r.viewBuilder.NewTextFieldView("Write the commit message", "")
-	r.utils.ValidateInput(commitDescription)
+	runSteps(
+		// Define scope for current branch in case it's empty
+		func() stepResult {
+			if !askScope || !trackBranch {
+				return skipStep
+			}
 
-	// Build commit message
+			choices := []ListItem{
+				{
+					T: "branch",
+					D: "use branch name as scope",
+				},
+				{
+					T: "custom",
+					D: "write a custom string to be the scope",
+				},
+			}
+
+			var answer ListItem
+			if scopeChoice != "" {
+				answer = r.viewBuilder.NewListViewWithSelection("This branch does not have scope defined yet.", choices, 16, scopeChoice)
+			} else {
+				answer = r.viewBuilder.NewListView("This branch does not have scope defined yet.", choices, 16)
+			}
+			if answer.T == BackSignal {
+				return backStep
+			}
+			r.validateInput(answer.T)
+
+			scopeChoice = answer.T
+			if answer.T == "branch" {
+				branchData.Scope = currentBranchName
+				r.draft.Scope = branchData.Scope
+			}
+			return nextStep
+		},
 
-	commitMsg := fmt.Sprintf(
-		"%s(%s): %s",
-		selectCommitType.T,
-		branchData.Scope,
-		commitDescription,
-	)
+		// A custom scope, also asked on a detached HEAD where it is not saved.
+		func() stepResult {
+			if !askScope || (trackBranch && scopeChoice != "custom") {
+				return skipStep
+			}
+
+			var newValue string
+			if branchData.Scope != "" && branchData.Scope != currentBranchName {
+				newValue = r.viewBuilder.NewTextFieldViewWithValue("Write a name for the scope", "", branchData.Scope, scopes)
+			} else {
+				newValue = r.viewBuilder.NewTextFieldView("Write a name for the scope", "", scopes)
+			}
+			if newValue == BackSignal {
+				return backStep
+			}
+			r.validateInput(newValue)
 
-	// offer to commit of just print the commit message
+			branchData.Scope = newValue
+			r.draft.Scope = branchData.Scope
+			return nextStep
+		},
 
-	choices := []ListItem{
-		{
-			T: "commit",
-			D: fmt.Sprintf("kcommit will call git commit with: %s", commitMsg),
+		// Resume the draft left on the branch.
+		func() stepResult {
+			if !trackBranch || branchData.Draft == nil {
+				return skipStep
+			}
+
+			draftChoice = r.offerDraft(*branchData.Draft, rules, draftChoice)
+			if draftChoice == BackSignal {
+				draftChoice = ""
+				return backStep
+			}
+
+			if draftChoice == "resume" {
+				r.draft = *branchData.Draft
+			} else {
+				r.draft = DraftDTO{Scope: branchData.Scope}
+			}
+			return nextStep
 		},
-		{
-			T: "just print",
-			D: "kcommit will not call git commit, just print the resulting commit message",
+
+		// Write the message on the composer: type, scope, description and body on a single screen.
+		func() stepResult {
+			message = CommitMessage{
+				Type:        r.draft.Type,
+				Scope:       r.draft.Scope,
+				Description: r.draft.Description,
+				Body:        r.draft.Body,
+				Ticket:      branchData.Ticket,
+			}
+
+			if rules.Ticket == nil {
+				message.Ticket = ""
+			}
+
+			actions := []ListItem{
+				{
+					T: "commit",
+					D: "kcommit will call git commit with the message above",
+				},
+				{
+					T: "just print",
+					D: "kcommit will not call git commit, just print the resulting commit message",
+				},
+			}
+
+			message, action = r.compose(ComposerOptions{
+				Title:                  "Write the commit message",
+				Message:                message,
+				Rules:                  rules,
+				Actions:                actions,
+				ScopeSuggestions:       scopes,
+				DescriptionSuggestions: descriptions,
+			})
+			if action == BackSignal {
+				return backStep
+			}
+			return nextStep
 		},
+	)
+
+	// This will set the scope to be saved and the time it was updated.
+	// Time updated is also used later to clear out old branches.
+	// A scope changed on the composer is kept for the next commits on the branch.
+	if trackBranch {
+		history.SetBranch(currentProjName, currentBranchName, message.Scope)
 	}
 
-	answer := r.viewBuilder.NewListView("This branch does not have scope defined yet.", choices, 16)
-	r.utils.ValidateInput(answer.T)
+	commitMsg := message.Format(rules)
 
-	if answer.T == "commit" {
-		msg, err := r.git.GitCommit(commitMsg)
-		if err != nil {
-			r.utils.HandleError(err, "Failed git commit")
+	if action == "commit" {
+		msg, committed := r.commit(commitMsg, rules, false)
+		if !committed {
+			return
+		}
+
+		// Only the messages that were committed count for the suggestions.
+		if trackBranch {
+			history.RecordType(currentProjName, currentBranchName, message.Type, time.Now())
+			history.RecordDescription(currentProjName, message.Description)
 		}
 		println(styles.Text(msg, styles.AquamarineColor))
 	} else {
 		println(styles.Text(commitMsg, styles.AquamarineColor))
 	}
 
+	// The message was used, the draft is not needed anymore.
+	if trackBranch {
+		history.SetDraft(currentProjName, currentBranchName, nil)
+	}
+
 	// Clean cache.
 	history.CleanOldBranches(time.Now())
 
@@ -214,3 +366,404 @@ func (r *Runner) Start() {
 
 	r.fileManager.WriteHistoryContent(h)
 }
+
+// compose shows the composer until one of the actions is chosen, the types to choose from are the ones of rules.
+// ctrl+e opens the message in the editor, then the composer is shown again to confirm it.
+// What is written is kept as the draft, so it is saved when kcommit is cancelled.
+func (r *Runner) compose(options ComposerOptions) (CommitMessage, string) {
+	rules := options.Rules
+	options.Types = r.utils.CommitTypeDTOsToListItems(rules.CommitTypeDTOs)
+
+	for {
+		result := r.viewBuilder.NewComposerView(options)
+
+		r.draft = DraftDTO{
+			Type:        result.Message.Type,
+			Scope:       result.Message.Scope,
+			Description: result.Message.Description,
+			Body:        result.Message.Body,
+		}
+
+		if result.Action != EditorSignal {
+			// BackSignal is returned as is, the caller shows the previous prompt.
+			r.validateInput(result.Action)
+			return result.Message, result.Action
+		}
+
+		edited, ok := r.editMessage(result.Message, rules)
+		if !ok {
+			r.validateInput(ExitSignal)
+			return result.Message, ExitSignal
+		}
+
+		options.Message = edited
+		options.Focus = ConfirmPane
+	}
+}
+
+// recentMessages parses the headers of the recent commits of the branch, the ones that do not follow the rules are left out.
+func (r *Runner) recentMessages(rules *CommitRulesDTO) []CommitMessage {
+	// A repository without commits has no log, there is nothing to suggest from it.
+	commits, _ := r.git.GetRecentCommits(RecentCommitsLimit)
+
+	var messages []CommitMessage
+	for _, commit := range commits {
+		if message := ParseCommitMessage(commit.Subject, rules); message.Type != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// descriptionSuggestions lists the descriptions written before on the project, the most recent first,
+// then the ones of the recent commits. history may be nil when it is not used.
+func descriptionSuggestions(history *History, projectName string, recent []CommitMessage) []string {
+	var suggestions []string
+	if history != nil {
+		suggestions = appendUnique(suggestions, history.RecentDescriptions(projectName)...)
+	}
+
+	for _, message := range recent {
+		suggestions = appendUnique(suggestions, message.Description)
+	}
+	return suggestions
+}
+
+// scopeSuggestions lists the scopes of the rules, then the ones saved for the branches of the project,
+// the most recent first, then the ones of the recent commits. The scopes of the rules come first
+// so the same spelling is used everywhere. history may be nil when it is not used.
+func scopeSuggestions(history *History, projectName string, rules *CommitRulesDTO, recent []CommitMessage) []string {
+	suggestions := appendUnique(nil, rules.Scopes...)
+
+	if history != nil {
+		branches := slices.Collect(maps.Values(history.Projects[projectName]))
+		slices.SortFunc(branches, func(a, b BranchDetail) int {
+			return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), strings.Compare(a.Scope, b.Scope))
+		})
+
+		for _, branch := range branches {
+			suggestions = appendUnique(suggestions, branch.Scope)
+		}
+	}
+
+	for _, message := range recent {
+		suggestions = appendUnique(suggestions, message.Scope)
+	}
+	return suggestions
+}
+
+// appendUnique appends the values that are not empty and not in list yet.
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if value != "" && !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// offerDraft asks to resume the draft left on the branch, selected is the earlier answer.
+func (r *Runner) offerDraft(draft DraftDTO, rules *CommitRulesDTO, selected string) string {
+	message := CommitMessage{Type: draft.Type, Scope: draft.Scope, Description: draft.Description}
+
+	choices := []ListItem{
+		{
+			T: "resume",
+			D: fmt.Sprintf("continue with: %s", message.Header(rules)),
+		},
+		{
+			T: "discard",
+			D: "start a new commit message",
+		},
+	}
+
+	var answer ListItem
+	if selected != "" {
+		answer = r.viewBuilder.NewListViewWithSelection("There is a draft for this branch.", choices, 16, selected)
+	} else {
+		answer = r.viewBuilder.NewListView("There is a draft for this branch.", choices, 16)
+	}
+	r.utils.ValidateInput(answer.T)
+
+	return answer.T
+}
+
+// askList shows a list that has no previous prompt to go back to, going back shows it again.
+func (r *Runner) askList(title string, choices []ListItem) ListItem {
+	for {
+		answer := r.viewBuilder.NewListView(title, choices, 16)
+		if answer.T != BackSignal {
+			r.utils.ValidateInput(answer.T)
+			return answer
+		}
+	}
+}
+
+// validateInput saves the draft before kcommit exits on cancel.
+func (r *Runner) validateInput(v string) {
+	if v == ExitSignal {
+		r.saveDraft()
+	}
+	r.utils.ValidateInput(v)
+}
+
+// saveDraft writes the draft to the history, so the next run can resume it.
+func (r *Runner) saveDraft() {
+	if r.history == nil || r.draft.Description == "" {
+		return
+	}
+
+	draft := r.draft
+	r.history.SetDraft(r.draftProject, r.draftBranch, &draft)
+
+	h, err := r.history.ToJson()
+	if err != nil {
+		return
+	}
+
+	r.fileManager.WriteHistoryContent(h)
+}
+
+// commitPreparedMessage offers to commit the message git prepared for a merge, cherry-pick or revert.
+// It returns true when the prepared message was used.
+func (r *Runner) commitPreparedMessage(state RepositoryState, rules *CommitRulesDTO) bool {
+	styles := DefaultStyles()
+
+	header, _, _ := strings.Cut(state.PreparedMessage, "\n")
+
+	choices := []ListItem{
+		{
+			T: "keep",
+			D: fmt.Sprintf("kcommit will call git commit with: %s", header),
+		},
+		{
+			T: "write",
+			D: "write a new message with kcommit",
+		},
+	}
+
+	answer := r.askList(fmt.Sprintf("A %s is in progress, keep the message prepared by git?", state.Operation), choices)
+
+	if answer.T != "keep" {
+		return false
+	}
+
+	msg, committed := r.commit(state.PreparedMessage, rules, false)
+	if committed {
+		println(styles.Text(msg, styles.AquamarineColor))
+	}
+
+	return true
+}
+
+// checkRepository exits early when kcommit does not run inside a git repository
+// and keeps the repository info for the next steps.
+func (r *Runner) checkRepository() {
+	info, err := r.git.GetRepositoryInfo()
+
+	if !r.git.IsGitRepository() {
+		if GitErrorCategoryOf(err) == GitNotInstalledError {
+			r.handleGitError(err, "")
+		}
+		r.utils.ExitWithError("Current directory is not a git repository")
+		return
+	}
+
+	if err != nil {
+		r.utils.HandleError(err, "Failed to read git repository info")
+	}
+	r.repository = info
+}
+
+// loadRules checks for rules on the repository root.
+// It may find .kcommitrc or not (not mandatory)
+// In case current project does not have .kcommitrc it should use a default config (DefaultRules)
+// More about kcommitrc on README.md.
+func (r *Runner) loadRules() *CommitRulesDTO {
+	rules := DefaultRules()
+
+	configPath := filepath.Join(r.repository.TopLevel, KcommitRcFileName)
+
+	hasCustomConfig, err := r.fileManager.CheckIfPathExists(configPath)
+	if err != nil {
+		r.utils.HandleError(err, "Failed load kcommitrc")
+	}
+
+	if hasCustomConfig {
+
+		customConfigStr, err := r.fileManager.ReadFileContent(configPath)
+		if err != nil {
+			r.utils.HandleError(err, "Failed to read .kcommitrc. Check if the formmat ir correct")
+		}
+
+		customRules, err := ParseJSONContent[CommitRulesDTO](customConfigStr)
+		if err != nil {
+			r.utils.HandleError(err, "Failed to parse .kcommitrc")
+		}
+
+		var sharedRules *CommitRulesDTO
+		if customRules.Extends != "" {
+			var stale bool
+			sharedRules, stale, err = LoadSharedRules(r.fileManager, customRules.Extends, r.repository.TopLevel, customRules.ExtendsChecksum)
+			if err != nil {
+				r.utils.HandleError(err, "Failed to load the rules extended by .kcommitrc")
+			}
+
+			if stale {
+				styles := DefaultStyles()
+				println(styles.Text(fmt.Sprintf("Could not fetch %s, using the cached copy", customRules.Extends), styles.PeachColor))
+			}
+		}
+
+		rules, err = ResolveRules(customRules, sharedRules)
+		if err != nil {
+			r.utils.HandleError(err, "Failed to load the preset of .kcommitrc")
+		}
+	}
+
+	return rules
+}
+
+// selectRecentCommit lists the recent commits of the current branch and returns the chosen one,
+// selected is the hash of the earlier answer. It returns false when the user goes back.
+func (r *Runner) selectRecentCommit(title string, selected LogEntry) (LogEntry, bool) {
+	commits, err := r.git.GetRecentCommits(RecentCommitsLimit)
+	if err != nil {
+		r.utils.HandleError(err, "Failed to list recent commits")
+	}
+
+	if len(commits) == 0 {
+		r.utils.ExitWithError("Current branch does not have commits yet")
+		return LogEntry{}, true
+	}
+
+	commitOptions := []ListItem{}
+	for _, c := range commits {
+		commitOptions = append(commitOptions, ListItem{T: c.ShortHash, D: c.Subject})
+	}
+
+	var selectedCommit ListItem
+	if selected.ShortHash != "" {
+		selectedCommit = r.viewBuilder.NewListViewWithSelection(title, commitOptions, 32, selected.ShortHash)
+	} else {
+		selectedCommit = r.viewBuilder.NewListView(title, commitOptions, 32)
+	}
+	if selectedCommit.T == BackSignal {
+		return LogEntry{}, false
+	}
+	r.utils.ValidateInput(selectedCommit.T)
+
+	for _, c := range commits {
+		if c.ShortHash == selectedCommit.T {
+			return c, true
+		}
+	}
+
+	r.utils.ExitWithError(fmt.Sprintf("%s is not one of the recent commits", selectedCommit.T))
+	return LogEntry{}, true
+}
+
+// commit calls git commit and reacts to the kind of failure:
+// - nothing to commit: offers to stage all changes and try again.
+// - rejected by a hook: shows the hook output as is.
+// - git not installed: shows how to install it.
+// It returns false when nothing was committed.
+func (r *Runner) commit(commitMsg string, rules *CommitRulesDTO, amend bool) (string, bool) {
+	args := r.commitArgs(rules)
+	staged := false
+
+	for {
+		// git output, e.g. from pre-commit hooks, is shown live while the spinner runs.
+		msg, err := r.viewBuilder.NewProgressView("Running git commit...", func(output io.Writer) (string, error) {
+			r.git.SetOutput(output)
+			defer r.git.SetOutput(nil)
+
+			if amend {
+				return r.git.GitCommitAmend(commitMsg, args...)
+			}
+			return r.git.GitCommit(commitMsg, args...)
+		})
+
+		if err == nil {
+			return msg, true
+		}
+
+		switch GitErrorCategoryOf(err) {
+		case NothingToCommitError:
+			if staged || !r.offerStaging() {
+				r.saveDraft()
+				r.utils.ExitWithError("Nothing to commit")
+				return "", false
+			}
+			staged = true
+			continue
+
+		case HookFailedError:
+			// The output is shown again with the error, the streamed lines may have scrolled away.
+			var gitErr *GitError
+			errors.As(err, &gitErr)
+			r.saveDraft()
+			r.utils.ExitWithError(fmt.Sprintf("Commit rejected by git hook:\n%s\n%s", gitErr.Output(), r.saveLastMessage(commitMsg)))
+			return "", false
+
+		default:
+			r.saveDraft()
+			r.handleGitError(err, fmt.Sprintf("Failed git commit. %s", r.saveLastMessage(commitMsg)))
+			return "", false
+		}
+	}
+}
+
+// saveLastMessage keeps the message of a failed commit so it can be reused with git commit -F.
+func (r *Runner) saveLastMessage(commitMsg string) string {
+	path, err := r.fileManager.WriteLastMessage(commitMsg)
+	if err != nil {
+		return fmt.Sprintf("The commit message was:\n%s", commitMsg)
+	}
+	return fmt.Sprintf("The commit message was saved, retry with: git commit -F %s", path)
+}
+
+// offerStaging asks to stage all changes when there is nothing to commit.
+func (r *Runner) offerStaging() bool {
+	choices := []ListItem{
+		{
+			T: "stage all",
+			D: "kcommit will call git add --all and commit again",
+		},
+		{
+			T: "cancel",
+			D: "do not commit",
+		},
+	}
+
+	answer := r.askList("Nothing is staged for commit.", choices)
+
+	if answer.T != "stage all" {
+		return false
+	}
+
+	if err := r.git.StageAll(); err != nil {
+		r.handleGitError(err, "Failed git add")
+		return false
+	}
+
+	return true
+}
+
+// handleGitError shows an install hint when git is missing, otherwise the git error.
+func (r *Runner) handleGitError(err error, message string) {
+	if GitErrorCategoryOf(err) == GitNotInstalledError {
+		r.utils.ExitWithError(fmt.Sprintf("git is not installed. Install it from https://git-scm.com or set %s=%s", GitBackendEnv, GoGitBackend))
+		return
+	}
+
+	r.utils.HandleError(err, message)
+}
+
+func (r *Runner) commitArgs(rules *CommitRulesDTO) []string {
+	options := CommitOptionsDTO{}
+	if rules.CommitOptions != nil {
+		options = *rules.CommitOptions
+	}
+	return options.Merge(r.commitOptions).Args()
+}
@@ -0,0 +1,65 @@
+package src
+
+// Amend rewrites the message of the HEAD commit using the same prompts as Start,
+// pre-filled with the values parsed from the current message.
+func (r *Runner) Amend() {
+	styles := DefaultStyles()
+
+	r.checkRepository()
+
+	r.fileManager.BasicSetup()
+
+	rules := r.loadRules()
+
+	lastMessage, err := r.git.GetLastCommitMessage()
+	if err != nil {
+		r.utils.HandleError(err, "Failed to read last commit message")
+	}
+
+	message := ParseCommitMessage(lastMessage, rules)
+
+	// Edit the message on the composer, pre-filled with the current one.
+
+	actions := []ListItem{
+		{
+			T: "amend",
+			D: "kcommit will call git commit --amend with the message above",
+		},
+		{
+			T: "just print",
+			D: "kcommit will not call git commit, just print the resulting commit message",
+		},
+	}
+
+	recent := r.recentMessages(rules)
+
+	var action string
+
+	// There is no previous prompt, going back shows the composer again.
+	runSteps(func() stepResult {
+		message, action = r.compose(ComposerOptions{
+			Title:                  "Amend the last commit",
+			Message:                message,
+			Rules:                  rules,
+			Actions:                actions,
+			ScopeSuggestions:       scopeSuggestions(nil, "", rules, recent),
+			DescriptionSuggestions: descriptionSuggestions(nil, "", recent),
+		})
+		if action == BackSignal {
+			return backStep
+		}
+		return nextStep
+	})
+
+	commitMsg := message.Format(rules)
+
+	if action == "amend" {
+		msg, committed := r.commit(commitMsg, rules, true)
+		if !committed {
+			return
+		}
+		println(styles.Text(msg, styles.AquamarineColor))
+	} else {
+		println(styles.Text(commitMsg, styles.AquamarineColor))
+	}
+}
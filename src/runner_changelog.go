@@ -0,0 +1,45 @@
+package src
+
+import (
+	"fmt"
+)
+
+// Changelog prints the commits of revisionRange grouped by type and scope, as Markdown or JSON.
+// Without a range it covers the commits since the latest tag, or every commit when there is none.
+func (r *Runner) Changelog(revisionRange string, asJSON bool) {
+	r.checkRepository()
+
+	rules := r.loadRules()
+
+	if revisionRange == "" {
+		tag, err := r.git.GetLatestTag()
+		if err != nil {
+			r.utils.HandleError(err, "Failed to read the latest tag")
+		}
+
+		revisionRange = "HEAD"
+		if tag != "" {
+			revisionRange = tag + "..HEAD"
+		}
+	}
+
+	commits, err := r.git.GetCommits(revisionRange)
+	if err != nil {
+		r.handleGitError(err, "Failed to read the commits of "+revisionRange)
+		return
+	}
+
+	changelog := BuildChangelog(revisionRange, commits, rules)
+
+	if !asJSON {
+		fmt.Fprint(r.output, changelog.Markdown())
+		return
+	}
+
+	content, err := changelog.JSON()
+	if err != nil {
+		r.utils.HandleError(err, "Failed to write the changelog")
+		return
+	}
+	fmt.Fprintln(r.output, content)
+}
@@ -0,0 +1,85 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+)
+
+// editMessage opens the message in the editor and reads it back.
+// The editor is opened again, with the problem on top, until the message is valid.
+// It returns false when the message was emptied to cancel.
+func (r *Runner) editMessage(message CommitMessage, rules *CommitRulesDTO) (CommitMessage, bool) {
+	var problem error
+
+	for {
+		raw, err := r.viewBuilder.NewEditorView(editorContent(message, rules, problem))
+		if err != nil {
+			r.utils.HandleError(err, "Failed to open the editor")
+		}
+
+		text := stripCommentLines(raw)
+		if text == "" {
+			return message, false
+		}
+
+		// The ticket comes from the branch, its trailer is added when the message is formatted.
+		edited := ParseCommitMessage(text, rules)
+		edited.Ticket = message.Ticket
+
+		problem = edited.Validate(rules)
+		if problem == nil {
+			return edited, true
+		}
+
+		message = edited
+		if message.Type == "" {
+			// Keep what was written as is, so it is not lost on the next round.
+			message = CommitMessage{Description: text, Ticket: edited.Ticket}
+		}
+	}
+}
+
+// editorContent is the message shown in the editor, followed by a commented help block.
+func editorContent(message CommitMessage, rules *CommitRulesDTO, problem error) string {
+	var b strings.Builder
+
+	if message.Type != "" {
+		b.WriteString(message.Header(rules))
+	} else {
+		b.WriteString(message.Description)
+	}
+	b.WriteString("\n")
+
+	if body := strings.TrimSpace(message.Body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+
+	template := rules.HeaderTemplate
+	if template == "" {
+		template = DefaultHeaderTemplate
+	}
+
+	b.WriteString("\n")
+	if problem != nil {
+		fmt.Fprintf(&b, "# Error: %v\n#\n", problem)
+	}
+	fmt.Fprintf(&b, "# The first line is the header, it must follow: %s\n", template)
+	b.WriteString("# Leave an empty line after it to write the body.\n")
+	b.WriteString("# Lines starting with '#' are ignored, an empty message cancels the commit.\n")
+
+	if len(rules.CommitTypeDTOs) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("#\n# Allowed types:\n")
+
+	width := 0
+	for _, commitType := range rules.CommitTypeDTOs {
+		width = max(width, len(commitType.Type))
+	}
+	for _, commitType := range rules.CommitTypeDTOs {
+		fmt.Fprintf(&b, "#   %-*s  %s\n", width, commitType.Type, commitType.Label())
+	}
+
+	return b.String()
+}
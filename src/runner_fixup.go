@@ -0,0 +1,116 @@
+package src
+
+import (
+	"fmt"
+)
+
+const (
+	FixupCommit  = "fixup"
+	SquashCommit = "squash"
+)
+
+// Fixup creates a fixup! or squash! commit targeting one of the recent commits on the branch,
+// optionally followed by an autosquash rebase.
+func (r *Runner) Fixup(kind string) {
+	styles := DefaultStyles()
+
+	r.checkRepository()
+
+	rules := r.loadRules()
+
+	var target LogEntry
+	var squashMsg string
+	var answer ListItem
+
+	// The prompts run as steps, going back shows the previous one with its answer selected.
+
+	runSteps(
+		func() stepResult {
+			selected, ok := r.selectRecentCommit(fmt.Sprintf("Choose the commit to %s", kind), target)
+			if !ok {
+				return backStep
+			}
+			target = selected
+			return nextStep
+		},
+
+		// The squash message is kept on the body of the commit.
+		func() stepResult {
+			if kind != SquashCommit {
+				return skipStep
+			}
+
+			var value string
+			if squashMsg != "" {
+				value = r.viewBuilder.NewTextFieldViewWithValue("Write the message to add to the squashed commit", "", squashMsg, nil)
+			} else {
+				value = r.viewBuilder.NewTextFieldView("Write the message to add to the squashed commit", "", nil)
+			}
+			if value == BackSignal {
+				return backStep
+			}
+			r.utils.ValidateInput(value)
+
+			squashMsg = value
+			return nextStep
+		},
+
+		// offer to commit, commit and autosquash or just print the commit message
+		func() stepResult {
+			choices := []ListItem{
+				{
+					T: "commit",
+					D: fmt.Sprintf("kcommit will call git commit with: %s! %s", kind, target.Subject),
+				},
+				{
+					T: "commit and autosquash",
+					D: fmt.Sprintf("kcommit will commit and rebase with --autosquash onto %s", target.ShortHash),
+				},
+				{
+					T: "just print",
+					D: "kcommit will not call git commit, just print the resulting commit message",
+				},
+			}
+
+			if answer.T != "" {
+				answer = r.viewBuilder.NewListViewWithSelection(fmt.Sprintf("Create %s commit?", kind), choices, 16, answer.T)
+			} else {
+				answer = r.viewBuilder.NewListView(fmt.Sprintf("Create %s commit?", kind), choices, 16)
+			}
+			if answer.T == BackSignal {
+				answer = ListItem{}
+				return backStep
+			}
+			r.utils.ValidateInput(answer.T)
+			return nextStep
+		},
+	)
+
+	// Build commit message
+	// git recognizes the target by its subject, the squash message is kept on the body.
+
+	commitMsg := fmt.Sprintf("%s! %s", kind, target.Subject)
+
+	if kind == SquashCommit {
+		commitMsg = fmt.Sprintf("%s\n\n%s", commitMsg, squashMsg)
+	}
+
+	if answer.T == "just print" {
+		println(styles.Text(commitMsg, styles.AquamarineColor))
+		return
+	}
+
+	msg, committed := r.commit(commitMsg, rules, false)
+	if !committed {
+		return
+	}
+	println(styles.Text(msg, styles.AquamarineColor))
+
+	if answer.T == "commit and autosquash" {
+		msg, err := r.git.GitAutosquash(target.Hash)
+		if err != nil {
+			r.utils.HandleError(err, "Failed git rebase --autosquash")
+		}
+		println(styles.Text(msg, styles.AquamarineColor))
+	}
+}
@@ -0,0 +1,89 @@
+package src
+
+import (
+	"fmt"
+)
+
+// Revert reverts one of the recent commits on the branch and commits it with a
+// revert(scope): <original header> message.
+func (r *Runner) Revert() {
+	styles := DefaultStyles()
+
+	r.checkRepository()
+
+	rules := r.loadRules()
+
+	var target LogEntry
+	var answer ListItem
+
+	// The revert is only applied once it is confirmed, so cancelling leaves the repository as it was.
+
+	runSteps(
+		func() stepResult {
+			selected, ok := r.selectRecentCommit("Choose the commit to revert", target)
+			if !ok {
+				return backStep
+			}
+			target = selected
+			return nextStep
+		},
+
+		// offer to commit or just print the commit message
+		func() stepResult {
+			choices := []ListItem{
+				{
+					T: "commit",
+					D: fmt.Sprintf("kcommit will call git revert and commit with: %s", revertMessage(target, rules).Header(rules)),
+				},
+				{
+					T: "just print",
+					D: "kcommit will not call git revert, just print the resulting commit message",
+				},
+			}
+
+			if answer.T != "" {
+				answer = r.viewBuilder.NewListViewWithSelection("Commit the revert?", choices, 16, answer.T)
+			} else {
+				answer = r.viewBuilder.NewListView("Commit the revert?", choices, 16)
+			}
+			if answer.T == BackSignal {
+				answer = ListItem{}
+				return backStep
+			}
+			r.utils.ValidateInput(answer.T)
+			return nextStep
+		},
+	)
+
+	commitMsg := revertMessage(target, rules).Format(rules)
+
+	if answer.T != "commit" {
+		println(styles.Text(commitMsg, styles.AquamarineColor))
+		return
+	}
+
+	// Apply the revert without committing, the message is built by kcommit.
+
+	if _, err := r.git.GitRevertNoCommit(target.Hash); err != nil {
+		r.utils.HandleError(err, "Failed git revert")
+		return
+	}
+
+	msg, committed := r.commit(commitMsg, rules, false)
+	if !committed {
+		return
+	}
+	println(styles.Text(msg, styles.AquamarineColor))
+}
+
+// revertMessage keeps the scope of the reverted commit and its header as the description.
+func revertMessage(target LogEntry, rules *CommitRulesDTO) CommitMessage {
+	original := ParseCommitMessage(target.Subject, rules)
+
+	return CommitMessage{
+		Type:        "revert",
+		Scope:       original.Scope,
+		Description: target.Subject,
+		Body:        fmt.Sprintf("This reverts commit %s.", target.Hash),
+	}
+}
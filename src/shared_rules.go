@@ -0,0 +1,141 @@
+package src
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SharedRulesTimeout is how long kcommit waits for shared rules before falling back to the cached copy.
+const SharedRulesTimeout = 5 * time.Second
+
+// SharedRulesMaxSize is the largest shared rules file kcommit reads from a URL.
+const SharedRulesMaxSize = 1 << 20
+
+var sharedRulesClient = &http.Client{Timeout: SharedRulesTimeout}
+
+// LoadSharedRules reads the rules that .kcommitrc extends. A path is relative to dir, the repository root.
+// A URL is fetched and cached under ~/.kcommit/cache, the cached copy is used when the fetch fails
+// and it is reported with stale. When checksum is set, the content must match it and a cached copy
+// that matches is used without fetching.
+func LoadSharedRules(fm FileManagerInterface, source, dir, checksum string) (rules *CommitRulesDTO, stale bool, err error) {
+	var content string
+
+	if isURL(source) {
+		content, stale, err = fetchSharedRules(fm, source, checksum)
+	} else {
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(dir, source)
+		}
+
+		content, err = fm.ReadFileContent(source)
+		if err == nil {
+			err = verifyChecksum(content, checksum)
+		}
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("LoadSharedRules -> %v", err)
+	}
+
+	rules, err = ParseJSONContent[CommitRulesDTO](content)
+	if err != nil {
+		return nil, false, fmt.Errorf("LoadSharedRules -> %s %v", source, err)
+	}
+
+	if rules.Extends != "" {
+		return nil, false, fmt.Errorf("LoadSharedRules -> %s can not extend other rules", source)
+	}
+
+	// Options such as noVerify or author change how every repository commits, they are set by each one.
+	if rules.CommitOptions != nil {
+		return nil, false, fmt.Errorf("LoadSharedRules -> %s can not set commitOptions", source)
+	}
+
+	return rules, stale, nil
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func fetchSharedRules(fm FileManagerInterface, url, checksum string) (string, bool, error) {
+	cacheName := sharedRulesCacheName(url)
+
+	cached, err := fm.ReadCacheContent(cacheName)
+	if err != nil {
+		return "", false, fmt.Errorf("fetchSharedRules -> %v", err)
+	}
+
+	// Pinned content does not change, the cached copy is enough.
+	if checksum != "" && cached != "" && verifyChecksum(cached, checksum) == nil {
+		return cached, false, nil
+	}
+
+	content, fetchErr := fetch(url)
+	if fetchErr == nil {
+		fetchErr = verifyChecksum(content, checksum)
+	}
+
+	if fetchErr != nil {
+		if cached == "" || verifyChecksum(cached, checksum) != nil {
+			return "", false, fmt.Errorf("fetchSharedRules -> %v", fetchErr)
+		}
+		return cached, true, nil
+	}
+
+	if err := fm.WriteCacheContent(cacheName, content); err != nil {
+		return "", false, fmt.Errorf("fetchSharedRules -> %v", err)
+	}
+
+	return content, false, nil
+}
+
+func fetch(url string) (string, error) {
+	response, err := sharedRulesClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("fetch -> %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch -> %s %s", url, response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, SharedRulesMaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("fetch -> %v", err)
+	}
+
+	if len(body) > SharedRulesMaxSize {
+		return "", fmt.Errorf("fetch -> %s is larger than %d bytes", url, SharedRulesMaxSize)
+	}
+	return string(body), nil
+}
+
+// sharedRulesCacheName names the cached copy of a URL after its hash.
+func sharedRulesCacheName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// verifyChecksum checks the sha256 of content against checksum, written as sha256:<hex> or <hex>.
+// An empty checksum accepts any content.
+func verifyChecksum(content, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	actual := hex.EncodeToString(sum[:])
+
+	if !strings.EqualFold(strings.TrimPrefix(checksum, "sha256:"), actual) {
+		return fmt.Errorf("verifyChecksum -> expected %s, got sha256:%s", checksum, actual)
+	}
+	return nil
+}
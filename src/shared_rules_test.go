@@ -0,0 +1,149 @@
+package src
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sharedRulesContent = `{"commitTypes": [{"type": "story", "description": "A user story."}]}`
+
+func sharedRulesChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func testFileManager(t *testing.T) *FileManager {
+	dir := t.TempDir()
+	return &FileManager{KcommitDir: dir, KcommitCache: filepath.Join(dir, KcommitCacheDirName)}
+}
+
+func TestLoadSharedRulesFromURL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(sharedRulesContent))
+	}))
+
+	fm := testFileManager(t)
+
+	rules, stale, err := LoadSharedRules(fm, server.URL+"/kcommit.json", "", "")
+	if err != nil || stale || rules.CommitTypeDTOs[0].Type != "story" {
+		t.Fatalf("expected the fetched rules, got %+v, %v, %v", rules, stale, err)
+	}
+
+	if cached, _ := fm.ReadCacheContent(sharedRulesCacheName(server.URL + "/kcommit.json")); cached != sharedRulesContent {
+		t.Errorf("expected the rules to be cached, got %q", cached)
+	}
+
+	// Offline the cached copy is used.
+	server.Close()
+
+	rules, stale, err = LoadSharedRules(fm, server.URL+"/kcommit.json", "", "")
+	if err != nil || !stale || rules.CommitTypeDTOs[0].Type != "story" {
+		t.Errorf("expected the cached rules, got %+v, %v, %v", rules, stale, err)
+	}
+
+	if _, _, err := LoadSharedRules(fm, server.URL+"/other.json", "", ""); err == nil {
+		t.Errorf("expected an error offline without a cached copy")
+	}
+
+	if requests != 1 {
+		t.Errorf("expected a single request to reach the server, got %d", requests)
+	}
+}
+
+func TestLoadSharedRulesPinnedChecksum(t *testing.T) {
+	content := sharedRulesContent
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	fm := testFileManager(t)
+	checksum := sharedRulesChecksum(sharedRulesContent)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := LoadSharedRules(fm, server.URL, "", checksum); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the pinned rules to be read from the cache, got %d requests", requests)
+	}
+
+	// Rules changed on the server do not match the pin.
+	content = `{"commitTypes": []}`
+	fm = testFileManager(t)
+
+	if _, _, err := LoadSharedRules(fm, server.URL, "", checksum); err == nil {
+		t.Errorf("expected an error when the rules do not match the checksum")
+	}
+}
+
+func TestLoadSharedRulesFromPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "kcommit.json"), []byte(sharedRulesContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fm := testFileManager(t)
+
+	rules, _, err := LoadSharedRules(fm, "kcommit.json", dir, sharedRulesChecksum(sharedRulesContent))
+	if err != nil || rules.CommitTypeDTOs[0].Type != "story" {
+		t.Errorf("expected the rules relative to the repository root, got %+v, %v", rules, err)
+	}
+
+	if _, _, err := LoadSharedRules(fm, "kcommit.json", dir, "sha256:00"); err == nil {
+		t.Errorf("expected an error when the file does not match the checksum")
+	}
+}
+
+func TestLoadSharedRulesRejectsUnsafeContent(t *testing.T) {
+	content := `{"commitOptions": {"noVerify": true}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	if _, _, err := LoadSharedRules(testFileManager(t), server.URL, "", ""); err == nil {
+		t.Errorf("expected an error when the shared rules set commit options")
+	}
+
+	content = strings.Repeat(" ", SharedRulesMaxSize) + sharedRulesContent
+	fm := testFileManager(t)
+
+	if _, _, err := LoadSharedRules(fm, server.URL, "", ""); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("expected an error when the shared rules are too large, got %v", err)
+	}
+
+	if cached, _ := fm.ReadCacheContent(sharedRulesCacheName(server.URL)); cached != "" {
+		t.Errorf("expected nothing cached, got %d bytes", len(cached))
+	}
+}
+
+func TestResolveRulesWithSharedRules(t *testing.T) {
+	shared := &CommitRulesDTO{
+		CommitTypeDTOs: []CommitTypeDTO{{Type: "story"}, {Type: "bug"}},
+		HeaderTemplate: "{type}: {description}",
+	}
+	custom := &CommitRulesDTO{CommitTypeDTOs: []CommitTypeDTO{{Type: "spike"}}}
+
+	rules, err := ResolveRules(custom, shared)
+	if err != nil || len(rules.CommitTypeDTOs) != 3 || rules.HeaderTemplate != "{type}: {description}" {
+		t.Errorf("expected the shared rules with the project's types added, got %+v, %v", rules, err)
+	}
+
+	custom.Preset = ConventionalPreset
+	if rules, err := ResolveRules(custom, shared); err != nil || rules.findType("feat").Type == "" || rules.findType("story").Type == "" {
+		t.Errorf("expected the shared rules on top of the preset, got %+v, %v", rules, err)
+	}
+}
@@ -0,0 +1,37 @@
+package src
+
+// stepResult tells runSteps where to go after a step.
+type stepResult int
+
+const (
+	// nextStep moves to the following step.
+	nextStep stepResult = iota
+	// backStep shows again the last step that was not skipped.
+	backStep
+	// skipStep moves on without the step being shown, going back does not stop on it.
+	skipStep
+)
+
+// runSteps runs the prompts of a command in order, so the user can go back with BackSignal.
+// Steps keep their answers outside, to show them selected when they are shown again.
+// Going back from the first step shows it again.
+func runSteps(steps ...func() stepResult) {
+	var shown []int
+
+	for index := 0; index < len(steps); {
+		switch steps[index]() {
+		case nextStep:
+			shown = append(shown, index)
+			index++
+
+		case skipStep:
+			index++
+
+		case backStep:
+			if len(shown) > 0 {
+				index = shown[len(shown)-1]
+				shown = shown[:len(shown)-1]
+			}
+		}
+	}
+}
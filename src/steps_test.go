@@ -0,0 +1,36 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRunSteps(t *testing.T) {
+	var shown []string
+
+	// Each step answers with the next result of its list.
+	step := func(name string, results ...stepResult) func() stepResult {
+		return func() stepResult {
+			result := results[0]
+			if len(results) > 1 {
+				results = results[1:]
+			}
+			if result != skipStep {
+				shown = append(shown, name)
+			}
+			return result
+		}
+	}
+
+	runSteps(
+		step("scope", backStep, nextStep),
+		step("draft", skipStep),
+		step("compose", backStep, nextStep),
+		step("confirm", backStep, nextStep),
+	)
+
+	expected := []string{"scope", "scope", "compose", "scope", "compose", "confirm", "compose", "confirm"}
+	if !reflect.DeepEqual(shown, expected) {
+		t.Errorf("expected steps %v, got %v", expected, shown)
+	}
+}
@@ -15,6 +15,13 @@ type Styles struct {
 	TitleStyle          lipgloss.Style
 	InputField          lipgloss.Style
 	InputFieldWithError lipgloss.Style
+	ErrorStyle          lipgloss.Style
+	PreviewStyle        lipgloss.Style
+
+	ComposerLabelStyle        lipgloss.Style
+	ComposerFocusedLabelStyle lipgloss.Style
+	ComposerFieldStyle        lipgloss.Style
+	ComposerFocusedFieldStyle lipgloss.Style
 
 	PaginationStyle   lipgloss.Style
 	HelpStyle         lipgloss.Style
@@ -29,33 +36,57 @@ type Styles struct {
 	ErrorColor      lipgloss.Color
 }
 
+// DefaultStyles returns the styles of the theme set on the user config.
 func DefaultStyles() *Styles {
+	return NewStyles(currentTheme)
+}
+
+// NewStyles builds the styles of a theme. Overrides of the base colors also change the colors derived from them.
+func NewStyles(theme *ThemeDTO) *Styles {
+	if theme == nil {
+		theme = &ThemeDTO{}
+	}
+
 	s := new(Styles)
 
-	s.PeachColor = lipgloss.Color("#F2B391")
-	s.CoralColor = lipgloss.Color("#F39194")
-	s.OrchidColor = lipgloss.Color("#E3B5BF")
-	s.ThistleColor = lipgloss.Color("#DAC3E9")
-	s.NyanzaColor = lipgloss.Color("#E9F2D0")
-	s.ErrorColor = lipgloss.Color("#FF99B8")
-	s.AquamarineColor = lipgloss.Color("#B4F8D5")
+	if !noColor() {
+		p := themePalette(theme)
+
+		s.PeachColor = lipgloss.Color(p.Peach)
+		s.CoralColor = lipgloss.Color(p.Coral)
+		s.OrchidColor = lipgloss.Color(p.Orchid)
+		s.ThistleColor = lipgloss.Color(p.Thistle)
+		s.NyanzaColor = lipgloss.Color(p.Nyanza)
+		s.ErrorColor = lipgloss.Color(p.Error)
+		s.AquamarineColor = lipgloss.Color(p.Aquamarine)
+		s.applyColors(theme.Colors)
 
-	s.BorderColor = s.OrchidColor
-	s.FooterColor = s.NyanzaColor
-	s.TitleColor = s.ThistleColor
-	s.SelectedTitleColor = s.OrchidColor
+		s.BorderColor = s.OrchidColor
+		s.FooterColor = s.NyanzaColor
+		s.TitleColor = s.ThistleColor
+		s.SelectedTitleColor = s.OrchidColor
+		s.applyColors(theme.Colors)
+	}
 
 	s.InputField = lipgloss.NewStyle().BorderForeground(s.BorderColor).BorderStyle(lipgloss.NormalBorder()).Padding(1).Width(80)
 	s.InputFieldWithError = lipgloss.NewStyle().BorderForeground(s.ErrorColor).BorderStyle(lipgloss.NormalBorder()).Padding(1).Width(80)
+	s.ErrorStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(s.ErrorColor)
+	s.PreviewStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(s.TitleColor)
 	s.FooterStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(s.FooterColor).Italic(true)
 	s.TitleStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(s.TitleColor).Bold(true)
 
+	s.ComposerLabelStyle = lipgloss.NewStyle().PaddingLeft(1).Width(14).Foreground(s.TitleColor)
+	s.ComposerFocusedLabelStyle = s.ComposerLabelStyle.Foreground(s.SelectedTitleColor).Bold(true)
+	s.ComposerFieldStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(s.TitleColor).PaddingLeft(1)
+	s.ComposerFocusedFieldStyle = s.ComposerFieldStyle.BorderForeground(s.SelectedTitleColor)
+
 	s.PaginationStyle = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
 	s.HelpStyle = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
 	s.SelectedItemStyle = lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder(), false, false, false, true).
 		BorderForeground(s.SelectedTitleColor).
 		Foreground(s.SelectedTitleColor).
+		Bold(noColor()).
 		Padding(0, 0, 0, 1)
 
 	return s
@@ -23,12 +23,20 @@ type textInputViewModel struct {
 	errors    bool
 }
 
-func TextFieldViewModel(question, placeHolder string, value *string) textInputViewModel {
+// TextFieldViewModel asks for a value, suggestions complete it with tab.
+func TextFieldViewModel(question, placeHolder string, value *string, suggestions []string) textInputViewModel {
 	ti := textinput.New()
 	ti.Placeholder = placeHolder
 	ti.Focus()
 	ti.CharLimit = 156
 	ti.Placeholder = placeHolder
+	ti.ShowSuggestions = len(suggestions) > 0
+	ti.SetSuggestions(suggestions)
+
+	// value may carry a previous answer, in that case it starts pre-filled.
+	if *value != "" {
+		ti.SetValue(*value)
+	}
 
 	return textInputViewModel{
 		textInput: ti,
@@ -62,6 +70,16 @@ func (m textInputViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 
+		case tea.KeyShiftTab, tea.KeyBackspace:
+			// backspace only goes back once there is nothing left to delete.
+			if msg.Type == tea.KeyBackspace && m.textInput.Value() != "" {
+				break
+			}
+
+			*m.endValue = BackSignal
+			m.quitting = true
+			return m, tea.Quit
+
 		case tea.KeyCtrlC, tea.KeyEsc:
 			*m.endValue = ExitSignal
 			m.quitting = true
@@ -91,13 +109,20 @@ func (m textInputViewModel) View() string {
 		lipgloss.Left,
 		m.styles.TitleStyle.Render(fmt.Sprintf("\n%s\n", m.question)),
 		inputField,
-		m.styles.FooterStyle.Render("\n(ctrl+c or esc to quit)"),
+		m.styles.FooterStyle.Render(m.footer()),
 	)
 }
 
-func TextFieldView(title, placeHolder string, endValue *string) {
+func (m textInputViewModel) footer() string {
+	if m.textInput.ShowSuggestions {
+		return "\n(tab to complete, up/down for other suggestions, shift+tab to go back, ctrl+c or esc to quit)"
+	}
+	return "\n(shift+tab to go back, ctrl+c or esc to quit)"
+}
+
+func TextFieldView(title, placeHolder string, endValue *string, suggestions []string) {
 
-	m := TextFieldViewModel(title, placeHolder, endValue)
+	m := TextFieldViewModel(title, placeHolder, endValue, suggestions)
 
 	if _, err := tea.NewProgram(m).Run(); err != nil {
 		fmt.Println("TextFieldView -> ", err)
@@ -0,0 +1,141 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	AutoTheme         = "auto"
+	DarkTheme         = "dark"
+	LightTheme        = "light"
+	HighContrastTheme = "high-contrast"
+)
+
+// palette holds the base colors of a theme, the other colors of Styles derive from them.
+type palette struct {
+	Peach, Coral, Orchid, Thistle, Nyanza, Aquamarine, Error string
+}
+
+var themes = map[string]palette{
+	DarkTheme: {
+		Peach:      "#F2B391",
+		Coral:      "#F39194",
+		Orchid:     "#E3B5BF",
+		Thistle:    "#DAC3E9",
+		Nyanza:     "#E9F2D0",
+		Aquamarine: "#B4F8D5",
+		Error:      "#FF99B8",
+	},
+	LightTheme: {
+		Peach:      "#B3541E",
+		Coral:      "#C2414B",
+		Orchid:     "#9C3F74",
+		Thistle:    "#5B3E8C",
+		Nyanza:     "#4F6B2A",
+		Aquamarine: "#1F7A5A",
+		Error:      "#C0003C",
+	},
+	HighContrastTheme: {
+		Peach:      "#FFAF00",
+		Coral:      "#FF5F5F",
+		Orchid:     "#FF5FFF",
+		Thistle:    "#FFFFFF",
+		Nyanza:     "#FFFF00",
+		Aquamarine: "#00FF87",
+		Error:      "#FF0000",
+	},
+}
+
+// currentTheme is the theme of the user config, used by DefaultStyles.
+var currentTheme = &ThemeDTO{}
+
+// UnmarshalJSON accepts the theme as a name, "theme": "light", or as an object with overrides.
+func (t *ThemeDTO) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = ThemeDTO{Name: name}
+		return nil
+	}
+
+	type theme ThemeDTO
+	return json.Unmarshal(data, (*theme)(t))
+}
+
+// SetTheme checks the theme of the user config and uses it on every view from now on.
+func SetTheme(theme *ThemeDTO) error {
+	if theme == nil {
+		theme = &ThemeDTO{}
+	}
+
+	if _, ok := themes[theme.Name]; !ok && theme.Name != "" && theme.Name != AutoTheme {
+		return fmt.Errorf("SetTheme -> unknown theme %q, use one of %s, %s, %s or %s", theme.Name, AutoTheme, DarkTheme, LightTheme, HighContrastTheme)
+	}
+
+	colors := new(Styles).colors()
+	for name := range theme.Colors {
+		if _, ok := colors[name]; !ok {
+			return fmt.Errorf("SetTheme -> unknown color %q, use one of %s", name, strings.Join(colorNames(colors), ", "))
+		}
+	}
+
+	currentTheme = theme
+	return nil
+}
+
+// themePalette picks the palette of the theme, auto follows the background of the terminal.
+func themePalette(theme *ThemeDTO) palette {
+	if p, ok := themes[theme.Name]; ok {
+		return p
+	}
+
+	if lipgloss.HasDarkBackground() {
+		return themes[DarkTheme]
+	}
+	return themes[LightTheme]
+}
+
+// noColor follows https://no-color.org, colors are dropped and selections are shown in bold.
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// colors maps the names used on the user config to the colors of the styles.
+func (s *Styles) colors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"footerColor":        &s.FooterColor,
+		"borderColor":        &s.BorderColor,
+		"titleColor":         &s.TitleColor,
+		"selectedTitleColor": &s.SelectedTitleColor,
+		"peachColor":         &s.PeachColor,
+		"coralColor":         &s.CoralColor,
+		"orchidColor":        &s.OrchidColor,
+		"thistleColor":       &s.ThistleColor,
+		"nyanzaColor":        &s.NyanzaColor,
+		"aquamarineColor":    &s.AquamarineColor,
+		"errorColor":         &s.ErrorColor,
+	}
+}
+
+func (s *Styles) applyColors(overrides map[string]string) {
+	colors := s.colors()
+	for name, value := range overrides {
+		if color, ok := colors[name]; ok {
+			*color = lipgloss.Color(value)
+		}
+	}
+}
+
+func colorNames(colors map[string]*lipgloss.Color) []string {
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
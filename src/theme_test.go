@@ -0,0 +1,82 @@
+package src
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestUserConfigTheme(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected ThemeDTO
+	}{
+		{`{"theme": "light"}`, ThemeDTO{Name: LightTheme}},
+		{`{"theme": {"name": "dark", "colors": {"titleColor": "#FFFFFF"}}}`, ThemeDTO{Name: DarkTheme, Colors: map[string]string{"titleColor": "#FFFFFF"}}},
+	}
+
+	for _, test := range tests {
+		config, err := ParseJSONContent[UserConfigDTO](test.json)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", test.json, err)
+		}
+
+		if config.Theme == nil || config.Theme.Name != test.expected.Name || len(config.Theme.Colors) != len(test.expected.Colors) {
+			t.Errorf("expected theme %+v from %s, got %+v", test.expected, test.json, config.Theme)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(nil)
+
+	if err := SetTheme(&ThemeDTO{Name: "solarized"}); err == nil {
+		t.Errorf("expected an error for an unknown theme")
+	}
+
+	if err := SetTheme(&ThemeDTO{Name: DarkTheme, Colors: map[string]string{"titleColour": "#FFFFFF"}}); err == nil {
+		t.Errorf("expected an error for an unknown color")
+	}
+
+	if err := SetTheme(&ThemeDTO{Name: HighContrastTheme}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if color := DefaultStyles().ErrorColor; color != lipgloss.Color(themes[HighContrastTheme].Error) {
+		t.Errorf("expected the styles of the theme set, got %v", color)
+	}
+}
+
+func TestNewStyles(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	s := NewStyles(&ThemeDTO{Name: LightTheme, Colors: map[string]string{"orchidColor": "#111111", "titleColor": "#222222"}})
+
+	if s.PeachColor != lipgloss.Color(themes[LightTheme].Peach) {
+		t.Errorf("expected light theme colors, got %v", s.PeachColor)
+	}
+
+	if s.OrchidColor != "#111111" || s.BorderColor != "#111111" || s.SelectedTitleColor != "#111111" {
+		t.Errorf("expected the base color override to reach derived colors, got %v %v %v", s.OrchidColor, s.BorderColor, s.SelectedTitleColor)
+	}
+
+	if s.TitleColor != "#222222" || s.ThistleColor == "#222222" {
+		t.Errorf("expected only the title color to be overridden, got %v and %v", s.TitleColor, s.ThistleColor)
+	}
+}
+
+func TestNewStylesNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	s := NewStyles(&ThemeDTO{Name: DarkTheme, Colors: map[string]string{"titleColor": "#222222"}})
+
+	for name, color := range s.colors() {
+		if *color != "" {
+			t.Errorf("expected no %s with NO_COLOR, got %v", name, *color)
+		}
+	}
+
+	if !s.SelectedItemStyle.GetBold() {
+		t.Errorf("expected selections in bold with NO_COLOR")
+	}
+}
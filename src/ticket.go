@@ -0,0 +1,57 @@
+package src
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExtractTicket returns the first issue key found in the branch name, or an empty string.
+func (t *TicketDTO) ExtractTicket(branchName string) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+
+	pattern := t.Pattern
+	if pattern == "" {
+		pattern = DefaultTicketPattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("ExtractTicket -> %v", err)
+	}
+
+	match := re.FindStringSubmatch(branchName)
+	if match == nil {
+		return "", nil
+	}
+
+	// Prefer the first capture group so patterns can strip decorations around the key.
+	for _, group := range match[1:] {
+		if group != "" {
+			return group, nil
+		}
+	}
+
+	return match[0], nil
+}
+
+// TrailerFor renders the ticket trailer appended to the commit message.
+// When the header template already carries the ticket no trailer is added,
+// unless one was explicitly configured.
+func (t *TicketDTO) TrailerFor(ticket, headerTemplate string) string {
+	if t == nil || ticket == "" {
+		return ""
+	}
+
+	trailer := t.Trailer
+	if trailer == "" {
+		if strings.Contains(headerTemplate, "{ticket}") {
+			return ""
+		}
+		trailer = DefaultTicketTrailer
+	}
+
+	return strings.ReplaceAll(trailer, "{ticket}", ticket)
+}
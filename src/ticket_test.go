@@ -0,0 +1,67 @@
+package src
+
+import "testing"
+
+func TestTicketExtractTicket(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		branch   string
+		expected string
+	}{
+		{"", "feature/ABC-123-add-cache", "ABC-123"},
+		{"", "fix/#456-crash", "#456"},
+		{"", "chore/update-deps", ""},
+		{`issue-([0-9]+)`, "issue-99-login", "99"},
+	}
+
+	for _, test := range tests {
+		ticket := &TicketDTO{Pattern: test.pattern}
+
+		got, err := ticket.ExtractTicket(test.branch)
+		if err != nil {
+			t.Fatalf("unexpected error for branch %q: %v", test.branch, err)
+		}
+
+		if got != test.expected {
+			t.Errorf("expected ticket %q for branch %q, got %q", test.expected, test.branch, got)
+		}
+	}
+}
+
+func TestTicketExtractTicketInvalidPattern(t *testing.T) {
+	ticket := &TicketDTO{Pattern: "("}
+
+	if _, err := ticket.ExtractTicket("ABC-1"); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestCommitMessageFormatWithTicket(t *testing.T) {
+	message := CommitMessage{Type: "feat", Scope: "cache", Description: "add eviction", Ticket: "ABC-123"}
+
+	tests := []struct {
+		rules    CommitRulesDTO
+		expected string
+	}{
+		{CommitRulesDTO{}, "feat(cache): add eviction"},
+		{CommitRulesDTO{Ticket: &TicketDTO{}}, "feat(cache): add eviction\n\nRefs: ABC-123"},
+		{CommitRulesDTO{Ticket: &TicketDTO{Trailer: "Closes {ticket}"}}, "feat(cache): add eviction\n\nCloses ABC-123"},
+		{
+			CommitRulesDTO{HeaderTemplate: "[{ticket}] {type}({scope}): {description}", Ticket: &TicketDTO{}},
+			"[ABC-123] feat(cache): add eviction",
+		},
+	}
+
+	for _, test := range tests {
+		if got := message.Format(&test.rules); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+
+	noTicket := CommitMessage{Type: "fix", Scope: "", Description: "typo"}
+	rules := CommitRulesDTO{HeaderTemplate: "[{ticket}] {type}({scope}): {description}"}
+
+	if got := noTicket.Format(&rules); got != "fix: typo" {
+		t.Errorf("expected empty placeholders to be dropped, got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package src
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// LoadUserConfig reads ~/.kcommit/.kcommit_config.json. The file is optional.
+func LoadUserConfig(fm FileManagerInterface) (*UserConfigDTO, error) {
+	configStr, err := fm.GetConfigContent()
+	if err != nil {
+		return nil, fmt.Errorf("LoadUserConfig -> %v", err)
+	}
+
+	if configStr == "" {
+		return &UserConfigDTO{}, nil
+	}
+
+	config, err := ParseJSONContent[UserConfigDTO](configStr)
+	if err != nil {
+		return nil, fmt.Errorf("LoadUserConfig -> %v", err)
+	}
+
+	return config, nil
+}
+
+// GitBackendName returns the backend chosen with KCOMMIT_GIT_BACKEND or, if unset, the user config.
+func (c *UserConfigDTO) GitBackendName() string {
+	if backend := os.Getenv(GitBackendEnv); backend != "" {
+		return backend
+	}
+	return c.GitBackend
+}
+
+// UsePlainPrompts tells whether to ask with PlainViewBuilder: when --plain is given, when the user config
+// asks for plain prompts or, by default, when kcommit does not run on a terminal.
+func (c *UserConfigDTO) UsePlainPrompts(plain bool, terminal bool) (bool, error) {
+	if plain {
+		return true, nil
+	}
+
+	switch c.Prompts {
+	case "", AutoPrompts:
+		return !terminal, nil
+	case InteractivePrompts:
+		return false, nil
+	case PlainPrompts:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("UsePlainPrompts -> unknown prompts %s, expected %s, %s or %s", c.Prompts, AutoPrompts, InteractivePrompts, PlainPrompts)
+}
+
+// IsTerminal tells whether kcommit reads from and writes to a terminal, the interactive screens need both.
+func IsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
@@ -24,7 +24,7 @@ func (u *Utils) CommitTypeDTOsToListItems(commitTypes []CommitTypeDTO) []ListIte
 	for _, commitType := range commitTypes {
 		listItems = append(listItems, ListItem{
 			T: commitType.Type,
-			D: commitType.Description,
+			D: commitType.Label(),
 		})
 	}
 	return listItems
@@ -1,8 +1,17 @@
 package src
 
+import (
+	"io"
+)
+
 type ViewBuilderInterface interface {
 	NewListView(title string, op []ListItem, height int) ListItem
-	NewTextFieldView(title, placeHolder string) string
+	NewListViewWithSelection(title string, op []ListItem, height int, selected string) ListItem
+	NewTextFieldView(title, placeHolder string, suggestions []string) string
+	NewTextFieldViewWithValue(title, placeHolder, value string, suggestions []string) string
+	NewEditorView(content string) (string, error)
+	NewComposerView(options ComposerOptions) ComposerResult
+	NewProgressView(title string, task func(output io.Writer) (string, error)) (string, error)
 }
 
 type ViewBuilder struct{}
@@ -17,8 +26,32 @@ func (b *ViewBuilder) NewListView(title string, op []ListItem, height int) ListI
 	return endValue
 }
 
-func (b *ViewBuilder) NewTextFieldView(title, placeHolder string) string {
+func (b *ViewBuilder) NewTextFieldView(title, placeHolder string, suggestions []string) string {
 	endValue := ""
-	TextFieldView(title, placeHolder, &endValue)
+	TextFieldView(title, placeHolder, &endValue, suggestions)
+	return endValue
+}
+
+func (b *ViewBuilder) NewListViewWithSelection(title string, op []ListItem, height int, selected string) ListItem {
+	endValue := ListItem{T: selected}
+	ListView(title, op, height, &endValue)
+	return endValue
+}
+
+func (b *ViewBuilder) NewTextFieldViewWithValue(title, placeHolder, value string, suggestions []string) string {
+	endValue := value
+	TextFieldView(title, placeHolder, &endValue, suggestions)
 	return endValue
 }
+
+func (b *ViewBuilder) NewEditorView(content string) (string, error) {
+	return EditorView(content)
+}
+
+func (b *ViewBuilder) NewComposerView(options ComposerOptions) ComposerResult {
+	return ComposerView(options)
+}
+
+func (b *ViewBuilder) NewProgressView(title string, task func(output io.Writer) (string, error)) (string, error) {
+	return ProgressView(title, task)
+}
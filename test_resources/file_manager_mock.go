@@ -17,7 +17,16 @@ type FileManagerMock struct {
 	GetHistoryContentReturns string
 	GetHistoryContentCalled  int
 
-	WriteHistoryContentWrittenContent string
+	WriteHistoryContentWrittenContent  string
+	WriteHistoryContentWrittenContents []string
+
+	GetConfigContentReturns string
+	GetConfigContentCalled  int
+
+	WriteLastMessageWrittenContent string
+
+	// CacheContents holds the files of the cache by name, written ones are added to it.
+	CacheContents map[string]string
 
 	BasicSetupReturnValue error
 	BasicSetupCalled      int
@@ -65,9 +74,32 @@ func (m *FileManagerMock) GetHistoryContent() (string, error) {
 
 func (m *FileManagerMock) WriteHistoryContent(content string) error {
 	m.WriteHistoryContentWrittenContent = content
+	m.WriteHistoryContentWrittenContents = append(m.WriteHistoryContentWrittenContents, content)
 	return nil
 }
 
+func (m *FileManagerMock) GetConfigContent() (string, error) {
+	m.GetConfigContentCalled += 1
+	return m.GetConfigContentReturns, nil
+}
+
+func (m *FileManagerMock) ReadCacheContent(name string) (string, error) {
+	return m.CacheContents[name], nil
+}
+
+func (m *FileManagerMock) WriteCacheContent(name, content string) error {
+	if m.CacheContents == nil {
+		m.CacheContents = map[string]string{}
+	}
+	m.CacheContents[name] = content
+	return nil
+}
+
+func (m *FileManagerMock) WriteLastMessage(content string) (string, error) {
+	m.WriteLastMessageWrittenContent = content
+	return "last_message", nil
+}
+
 func (m *FileManagerMock) BasicSetup() error {
 	m.BasicSetupCalled += 1
 	return m.BasicSetupReturnValue
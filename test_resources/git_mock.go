@@ -1,14 +1,55 @@
 package testresources
 
+import (
+	"io"
+
+	"kcommit/src"
+)
+
 type GitMock struct {
 	GetCurrentBranchReturnValue string
 	GetCurrentBranchCalled      int
 
 	GitCommitReturnValue string
 	GitCommitCalled      int
+	GitCommitCalledArgs  []string
+	// GitCommitReturnErrors are returned by the following GitCommit calls, one per call.
+	GitCommitReturnErrors []error
+
+	GitCommitAmendReturnValue string
+	GitCommitAmendCalled      int
+
+	GetLastCommitMessageReturnValue string
+	GetLastCommitMessageCalled      int
+
+	GetRecentCommitsReturnValue []src.LogEntry
+	GetRecentCommitsCalled      int
+
+	GetCommitsReturnValue []src.LogEntry
+	GetCommitsCalledWith  string
+	GetCommitsCalled      int
+
+	GetLatestTagReturnValue string
+	GetLatestTagCalled      int
+
+	GitAutosquashCalledWith string
+	GitAutosquashCalled     int
+
+	StageAllCalled int
+
+	SetOutputCalledWith []io.Writer
+
+	GitRevertNoCommitCalledWith string
+	GitRevertNoCommitCalled     int
 
 	IsGitRepositoryReturnValue bool
 	IsGitRepositoryCalled      int
+
+	GetRepositoryInfoReturnValue src.RepositoryInfo
+	GetRepositoryInfoCalled      int
+
+	GetRepositoryStateReturnValue src.RepositoryState
+	GetRepositoryStateCalled      int
 }
 
 func (g *GitMock) GetCurrentBranch() (string, error) {
@@ -16,13 +57,81 @@ func (g *GitMock) GetCurrentBranch() (string, error) {
 	return g.GetCurrentBranchReturnValue, nil
 }
 
-func (g *GitMock) GitCommit(msg string) (string, error) {
+func (g *GitMock) GitCommit(msg string, args ...string) (string, error) {
 	g.GitCommitCalled += 1
+	g.GitCommitCalledArgs = args
+
+	if len(g.GitCommitReturnErrors) > 0 {
+		err := g.GitCommitReturnErrors[0]
+		g.GitCommitReturnErrors = g.GitCommitReturnErrors[1:]
+		if err != nil {
+			return "", err
+		}
+	}
+
 	g.GitCommitReturnValue = msg
 	return g.GitCommitReturnValue, nil
 }
 
+func (g *GitMock) GitCommitAmend(msg string, args ...string) (string, error) {
+	g.GitCommitAmendCalled += 1
+	g.GitCommitAmendReturnValue = msg
+	return g.GitCommitAmendReturnValue, nil
+}
+
+func (g *GitMock) GetLastCommitMessage() (string, error) {
+	g.GetLastCommitMessageCalled += 1
+	return g.GetLastCommitMessageReturnValue, nil
+}
+
+func (g *GitMock) GetRecentCommits(limit int) ([]src.LogEntry, error) {
+	g.GetRecentCommitsCalled += 1
+	return g.GetRecentCommitsReturnValue, nil
+}
+
+func (g *GitMock) GetCommits(revisionRange string) ([]src.LogEntry, error) {
+	g.GetCommitsCalled += 1
+	g.GetCommitsCalledWith = revisionRange
+	return g.GetCommitsReturnValue, nil
+}
+
+func (g *GitMock) GetLatestTag() (string, error) {
+	g.GetLatestTagCalled += 1
+	return g.GetLatestTagReturnValue, nil
+}
+
+func (g *GitMock) GitAutosquash(target string) (string, error) {
+	g.GitAutosquashCalled += 1
+	g.GitAutosquashCalledWith = target
+	return "", nil
+}
+
+func (g *GitMock) GitRevertNoCommit(hash string) (string, error) {
+	g.GitRevertNoCommitCalled += 1
+	g.GitRevertNoCommitCalledWith = hash
+	return "", nil
+}
+
+func (g *GitMock) SetOutput(output io.Writer) {
+	g.SetOutputCalledWith = append(g.SetOutputCalledWith, output)
+}
+
+func (g *GitMock) StageAll() error {
+	g.StageAllCalled += 1
+	return nil
+}
+
 func (g *GitMock) IsGitRepository() bool {
 	g.IsGitRepositoryCalled += 1
 	return g.IsGitRepositoryReturnValue
 }
+
+func (g *GitMock) GetRepositoryInfo() (src.RepositoryInfo, error) {
+	g.GetRepositoryInfoCalled += 1
+	return g.GetRepositoryInfoReturnValue, nil
+}
+
+func (g *GitMock) GetRepositoryState() (src.RepositoryState, error) {
+	g.GetRepositoryStateCalled += 1
+	return g.GetRepositoryStateReturnValue, nil
+}
@@ -15,7 +15,7 @@ func (u *UtilsMock) CommitTypeDTOsToListItems(commitTypes []src.CommitTypeDTO) [
 	for _, commitType := range commitTypes {
 		listItems = append(listItems, src.ListItem{
 			T: commitType.Type,
-			D: commitType.Description,
+			D: commitType.Label(),
 		})
 	}
 	return listItems
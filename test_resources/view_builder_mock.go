@@ -1,6 +1,8 @@
 package testresources
 
 import (
+	"io"
+
 	"kcommit/src"
 )
 
@@ -9,16 +11,88 @@ type ViewBuilderMock struct {
 	NewListViewCalled           int
 	NewTextFieldViewReturnValue string
 	NewTextFieldViewCalled      int
+
+	// Queued answers are returned first, in order, before the single return values above.
+	NewListViewReturnValues      []string
+	NewTextFieldViewReturnValues []string
+
+	NewListViewWithSelectionCalledWith  []string
+	NewTextFieldViewWithValueCalledWith []string
+
+	// The suggestions given to every text field, with or without a value.
+	NewTextFieldViewSuggestions [][]string
+
+	NewEditorViewReturnValues []string
+	NewEditorViewCalledWith   []string
+
+	NewComposerViewReturnValues []src.ComposerResult
+	NewComposerViewCalledWith   []src.ComposerOptions
+
+	NewProgressViewCalled int
 }
 
 func (b *ViewBuilderMock) NewListView(title string, op []src.ListItem, height int) src.ListItem {
 	b.NewListViewCalled += 1
+	if len(b.NewListViewReturnValues) > 0 {
+		value := b.NewListViewReturnValues[0]
+		b.NewListViewReturnValues = b.NewListViewReturnValues[1:]
+		return src.ListItem{T: value}
+	}
 	return src.ListItem{
 		T: b.NewListViewReturnValue,
 	}
 }
 
-func (b *ViewBuilderMock) NewTextFieldView(title, placeHolder string) string {
+func (b *ViewBuilderMock) NewTextFieldView(title, placeHolder string, suggestions []string) string {
 	b.NewTextFieldViewCalled += 1
+	b.NewTextFieldViewSuggestions = append(b.NewTextFieldViewSuggestions, suggestions)
+	if len(b.NewTextFieldViewReturnValues) > 0 {
+		value := b.NewTextFieldViewReturnValues[0]
+		b.NewTextFieldViewReturnValues = b.NewTextFieldViewReturnValues[1:]
+		return value
+	}
 	return b.NewTextFieldViewReturnValue
 }
+
+func (b *ViewBuilderMock) NewListViewWithSelection(title string, op []src.ListItem, height int, selected string) src.ListItem {
+	b.NewListViewWithSelectionCalledWith = append(b.NewListViewWithSelectionCalledWith, selected)
+	return src.ListItem{
+		T: selected,
+	}
+}
+
+func (b *ViewBuilderMock) NewTextFieldViewWithValue(title, placeHolder, value string, suggestions []string) string {
+	b.NewTextFieldViewWithValueCalledWith = append(b.NewTextFieldViewWithValueCalledWith, value)
+	b.NewTextFieldViewSuggestions = append(b.NewTextFieldViewSuggestions, suggestions)
+	return value
+}
+
+// NewEditorView returns the next of NewEditorViewReturnValues, as if the user saved it in the editor.
+func (b *ViewBuilderMock) NewEditorView(content string) (string, error) {
+	b.NewEditorViewCalledWith = append(b.NewEditorViewCalledWith, content)
+	if len(b.NewEditorViewReturnValues) == 0 {
+		return content, nil
+	}
+
+	value := b.NewEditorViewReturnValues[0]
+	b.NewEditorViewReturnValues = b.NewEditorViewReturnValues[1:]
+	return value, nil
+}
+
+// NewComposerView returns the next of NewComposerViewReturnValues,
+// or the pre-filled message without an action when there are none left.
+func (b *ViewBuilderMock) NewComposerView(options src.ComposerOptions) src.ComposerResult {
+	b.NewComposerViewCalledWith = append(b.NewComposerViewCalledWith, options)
+	if len(b.NewComposerViewReturnValues) == 0 {
+		return src.ComposerResult{Message: options.Message}
+	}
+
+	value := b.NewComposerViewReturnValues[0]
+	b.NewComposerViewReturnValues = b.NewComposerViewReturnValues[1:]
+	return value
+}
+
+func (b *ViewBuilderMock) NewProgressView(title string, task func(output io.Writer) (string, error)) (string, error) {
+	b.NewProgressViewCalled += 1
+	return task(io.Discard)
+}